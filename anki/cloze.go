@@ -0,0 +1,126 @@
+package anki
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/hadith-api/normalize"
+)
+
+// maxClozes caps the number of cloze deletions, and so cards, per note
+const maxClozes = 20
+
+// transmissionTerms introduce a narrator in the isnad, in normalized form
+var transmissionTerms = map[string]bool{
+	"عن":     true,
+	"حدثنا":  true,
+	"حدثني":  true,
+	"اخبرنا": true,
+	"اخبرني": true,
+	"انبانا": true,
+	"سمعت":   true,
+	"ثنا":    true,
+}
+
+// speechTerms end the isnad by introducing the reported words or deed
+var speechTerms = map[string]bool{
+	"قال":  true,
+	"يقول": true,
+	"قالت": true,
+	"تقول": true,
+	"انه":  true,
+	"انها": true,
+	"ان":   true,
+}
+
+// salawat is the blessing that often follows the Prophet's name before the matn
+var salawat = []string{"صلي", "الله", "عليه", "وسلم"}
+
+// speechLookahead is how many words after the last narrator a speech term is looked for
+const speechLookahead = 8
+
+// splitMatn splits the Arabic words of a hadith into its isnad, the chain of
+// narrators, and its matn, the text itself. The isnad is taken to end at the
+// first speech term after the last transmission term in the first half of
+// the text, skipping a following blessing and "qala". Where no such boundary
+// is found the whole text is treated as matn.
+func splitMatn(words []string) (isnad, matn []string) {
+	normalized := make([]string, len(words))
+	for i, word := range words {
+		normalized[i] = normalize.Arabic(word)
+	}
+
+	last := -1
+	for i := 0; i < len(normalized)/2; i++ {
+		if transmissionTerms[normalized[i]] {
+			last = i
+		}
+	}
+	if last < 0 {
+		return nil, words
+	}
+
+	for i := last + 1; i < len(normalized) && i <= last+speechLookahead; i++ {
+		if !speechTerms[normalized[i]] {
+			continue
+		}
+
+		start := i + 1
+		if hasPhrase(normalized[start:], salawat) {
+			start += len(salawat)
+		}
+		if start < len(normalized) && normalized[start] == "قال" {
+			start++
+		}
+		if start >= len(words) {
+			break
+		}
+		return words[:start], words[start:]
+	}
+
+	return nil, words
+}
+
+// hasPhrase reports whether words starts with phrase
+func hasPhrase(words, phrase []string) bool {
+	if len(words) < len(phrase) {
+		return false
+	}
+	for i, word := range phrase {
+		if words[i] != word {
+			return false
+		}
+	}
+	return true
+}
+
+// Cloze turns the matn of an Arabic text into cloze deletions of about size
+// words each, leaving the isnad visible, and returns the number of
+// deletions. The text is HTML-escaped.
+func Cloze(arab string, size int) (string, int) {
+	isnad, matn := splitMatn(strings.Fields(arab))
+	if size < 1 {
+		size = 1
+	}
+	if chunks := (len(matn) + size - 1) / size; chunks > maxClozes {
+		size = (len(matn) + maxClozes - 1) / maxClozes
+	}
+
+	var parts []string
+	count := 0
+	if len(isnad) > 0 {
+		parts = append(parts, html.EscapeString(strings.Join(isnad, " ")))
+	}
+	for i := 0; i*size < len(matn); i++ {
+		end := (i + 1) * size
+		if end > len(matn) {
+			end = len(matn)
+		}
+		chunk := html.EscapeString(strings.Join(matn[i*size:end], " "))
+		parts = append(parts, fmt.Sprintf("{{c%d::%s}}", i+1, chunk))
+		count++
+	}
+
+	return strings.Join(parts, " "), count
+}
@@ -0,0 +1,58 @@
+package anki
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCloze(t *testing.T) {
+	tests := []struct {
+		arab  string
+		size  int
+		want  string
+		count int
+	}{
+		// The isnad ends at the speech term after the last narrator
+		{
+			"حدثنا مالك عن نافع عن ابن عمر أن رسول الله قال الخيل معقود في نواصيها الخير",
+			4,
+			"حدثنا مالك عن نافع عن ابن عمر أن {{c1::رسول الله قال الخيل}} {{c2::معقود في نواصيها الخير}}",
+			2,
+		},
+		// A blessing and "qala" after the speech term stay in the isnad
+		{
+			"حدثنا يحيى عن مالك أنه صلى الله عليه وسلم قال إنما الأعمال بالنيات",
+			2,
+			"حدثنا يحيى عن مالك أنه صلى الله عليه وسلم قال {{c1::إنما الأعمال}} {{c2::بالنيات}}",
+			2,
+		},
+		// Without an isnad the whole text is the matn
+		{"إنما الأعمال بالنيات", 1, "{{c1::إنما}} {{c2::الأعمال}} {{c3::بالنيات}}", 3},
+		{"حدثنا مالك قال", 5, "{{c1::حدثنا مالك قال}}", 1},
+		// A size below one deletes word by word
+		{"الدين النصيحة", 0, "{{c1::الدين}} {{c2::النصيحة}}", 2},
+		{"x<y & z", 10, "{{c1::x&lt;y &amp; z}}", 1},
+		{"", 3, "", 0},
+	}
+
+	for _, tt := range tests {
+		got, count := Cloze(tt.arab, tt.size)
+		if got != tt.want || count != tt.count {
+			t.Errorf("Cloze(%q, %d) = %q, %d; want %q, %d", tt.arab, tt.size, got, count, tt.want, tt.count)
+		}
+	}
+}
+
+func TestClozeLimit(t *testing.T) {
+	// 45 words deleted one by one would make more than maxClozes cards, so
+	// they are deleted three at a time
+	words := strings.Fields(strings.Repeat("كلمة ", 45))
+
+	got, count := Cloze(strings.Join(words, " "), 1)
+	if count != 15 {
+		t.Errorf("count = %d, want 15", count)
+	}
+	if !strings.HasPrefix(got, "{{c1::كلمة كلمة كلمة}} ") || !strings.HasSuffix(got, " {{c15::كلمة كلمة كلمة}}") {
+		t.Errorf("Cloze = %q", got)
+	}
+}
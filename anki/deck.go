@@ -0,0 +1,212 @@
+// Package anki writes hadiths as an Anki flashcard deck (.apkg): a zip
+// holding an SQLite collection with the notes, cards and note types.
+package anki
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// ContentType is the media type of an .apkg file
+const ContentType = "application/apkg"
+
+// Note is a hadith to memorize, with the Arabic on the front of its card and
+// the translation and reference on the back
+type Note struct {
+	// ID identifies the hadith, e.g. "malik:12". Notes keep their identity
+	// across exports, so re-importing a deck updates them.
+	ID          string
+	Arabic      string
+	Translation string
+	Reference   string
+	Tags        []string
+}
+
+// Deck is a named set of notes
+type Deck struct {
+	Name  string
+	Notes []Note
+	// Cloze, when positive, turns the matn of each note into cloze deletions
+	// of about this many words, with a card for each deletion
+	Cloze int
+}
+
+// Write writes the deck as an .apkg file. The SQLite collection is built in
+// a temporary directory, as it cannot be written straight into the zip.
+func Write(w io.Writer, d *Deck) error {
+	dir, err := os.MkdirTemp("", "anki")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "collection.anki2")
+	if err := writeCollection(path, d); err != nil {
+		return err
+	}
+
+	collection, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	z := zip.NewWriter(w)
+	f, err := z.Create("collection.anki2")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(collection); err != nil {
+		return err
+	}
+
+	// The deck has no images or sounds
+	media, err := z.Create("media")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(media, "{}"); err != nil {
+		return err
+	}
+
+	return z.Close()
+}
+
+// writeCollection creates the SQLite collection at path
+func writeCollection(path string, d *Deck) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(schema); err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	now := time.Now()
+	mod := now.Unix()
+	deckID := stableID(d.Name)
+
+	modelsJSON, err := json.Marshal(noteTypes(deckID, mod))
+	if err != nil {
+		return err
+	}
+	decksJSON, err := json.Marshal(map[string]deck{
+		"1":                           defaultDeck(mod),
+		strconv.FormatInt(deckID, 10): {ID: deckID, Name: d.Name, Mod: mod, Conf: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if _, err := db.Exec(`INSERT INTO col VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}')`,
+		midnight.Unix(), now.UnixMilli(), now.UnixMilli(),
+		collectionConf, string(modelsJSON), string(decksJSON), deckConf); err != nil {
+		return fmt.Errorf("failed to write collection: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	noteStmt, err := tx.Prepare(`INSERT INTO notes VALUES (?, ?, ?, ?, -1, ?, ?, ?, ?, 0, '')`)
+	if err != nil {
+		return err
+	}
+	cardStmt, err := tx.Prepare(`INSERT INTO cards VALUES (?, ?, ?, ?, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '')`)
+	if err != nil {
+		return err
+	}
+
+	// Note and card ids are millisecond timestamps in Anki; consecutive ids keep them unique
+	nextID := now.UnixMilli()
+	for i, note := range d.Notes {
+		modelID, front, cards := int64(basicModelID), html.EscapeString(note.Arabic), 1
+		if d.Cloze > 0 {
+			modelID = clozeModelID
+			front, cards = Cloze(note.Arabic, d.Cloze)
+		}
+
+		fields := strings.Join([]string{
+			front,
+			html.EscapeString(note.Translation),
+			html.EscapeString(note.Reference),
+		}, "\x1f")
+		sortField := stripHTML(front)
+
+		noteID := nextID
+		nextID++
+		if _, err := noteStmt.Exec(noteID, guid(note.ID), modelID, mod, tags(note.Tags),
+			fields, sortField, checksum(sortField)); err != nil {
+			return fmt.Errorf("failed to write note %s: %w", note.ID, err)
+		}
+
+		for ord := 0; ord < cards; ord++ {
+			if _, err := cardStmt.Exec(nextID, noteID, deckID, ord, mod, i+1); err != nil {
+				return fmt.Errorf("failed to write card of note %s: %w", note.ID, err)
+			}
+			nextID++
+		}
+	}
+
+	return tx.Commit()
+}
+
+// stableID derives a positive id from a name, small enough for JavaScript
+// numbers, so that the same deck keeps its id across exports
+func stableID(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64() >> 11)
+}
+
+// guid derives a note's globally unique id from the hadith it holds
+func guid(id string) string {
+	h := fnv.New64a()
+	h.Write([]byte("hadith-api:" + id))
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+// checksum is the first 32 bits of the SHA-1 of a note's sort field, which
+// Anki uses to find duplicates
+func checksum(sortField string) int64 {
+	sum := sha1.Sum([]byte(sortField))
+	n, _ := strconv.ParseInt(fmt.Sprintf("%x", sum[:4]), 16, 64)
+	return n
+}
+
+// tags formats tags as Anki stores them: space-separated with surrounding spaces
+func tags(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	cleaned := make([]string, len(list))
+	for i, tag := range list {
+		cleaned[i] = strings.ReplaceAll(strings.TrimSpace(tag), " ", "_")
+	}
+	return " " + strings.Join(cleaned, " ") + " "
+}
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// stripHTML returns the text of a field without markup
+func stripHTML(s string) string {
+	return html.UnescapeString(htmlTag.ReplaceAllString(s, ""))
+}
@@ -0,0 +1,228 @@
+package anki
+
+import "strconv"
+
+// schema creates the tables of an Anki 2.1 collection (schema version 11),
+// the format Anki still reads from the collection.anki2 file of an .apkg
+const schema = `
+CREATE TABLE col (
+	id     integer PRIMARY KEY,
+	crt    integer NOT NULL,
+	mod    integer NOT NULL,
+	scm    integer NOT NULL,
+	ver    integer NOT NULL,
+	dty    integer NOT NULL,
+	usn    integer NOT NULL,
+	ls     integer NOT NULL,
+	conf   text NOT NULL,
+	models text NOT NULL,
+	decks  text NOT NULL,
+	dconf  text NOT NULL,
+	tags   text NOT NULL
+);
+CREATE TABLE notes (
+	id    integer PRIMARY KEY,
+	guid  text NOT NULL,
+	mid   integer NOT NULL,
+	mod   integer NOT NULL,
+	usn   integer NOT NULL,
+	tags  text NOT NULL,
+	flds  text NOT NULL,
+	sfld  integer NOT NULL,
+	csum  integer NOT NULL,
+	flags integer NOT NULL,
+	data  text NOT NULL
+);
+CREATE TABLE cards (
+	id     integer PRIMARY KEY,
+	nid    integer NOT NULL,
+	did    integer NOT NULL,
+	ord    integer NOT NULL,
+	mod    integer NOT NULL,
+	usn    integer NOT NULL,
+	type   integer NOT NULL,
+	queue  integer NOT NULL,
+	due    integer NOT NULL,
+	ivl    integer NOT NULL,
+	factor integer NOT NULL,
+	reps   integer NOT NULL,
+	lapses integer NOT NULL,
+	left   integer NOT NULL,
+	odue   integer NOT NULL,
+	odid   integer NOT NULL,
+	flags  integer NOT NULL,
+	data   text NOT NULL
+);
+CREATE TABLE revlog (
+	id      integer PRIMARY KEY,
+	cid     integer NOT NULL,
+	usn     integer NOT NULL,
+	ease    integer NOT NULL,
+	ivl     integer NOT NULL,
+	lastIvl integer NOT NULL,
+	factor  integer NOT NULL,
+	time    integer NOT NULL,
+	type    integer NOT NULL
+);
+CREATE TABLE graves (
+	usn  integer NOT NULL,
+	oid  integer NOT NULL,
+	type integer NOT NULL
+);
+CREATE INDEX ix_notes_usn ON notes (usn);
+CREATE INDEX ix_cards_usn ON cards (usn);
+CREATE INDEX ix_revlog_usn ON revlog (usn);
+CREATE INDEX ix_cards_nid ON cards (nid);
+CREATE INDEX ix_cards_sched ON cards (did, queue, due);
+CREATE INDEX ix_revlog_cid ON revlog (cid);
+CREATE INDEX ix_notes_csum ON notes (csum);
+`
+
+// Note types, with fixed ids so that importing a newer deck updates the
+// notes of an earlier one instead of duplicating the note type
+const (
+	basicModelID = 1607392319001
+	clozeModelID = 1607392319002
+)
+
+// Card styling shared by both note types
+const css = `.card { font-family: sans-serif; font-size: 20px; text-align: center; }
+.arab { font-size: 30px; line-height: 1.8; direction: rtl; text-align: right; }
+.translation { text-align: justify; }
+.reference { margin-top: 1em; font-size: 14px; color: #777; }
+.cloze { font-weight: bold; color: #1565c0; }`
+
+// model is a note type in the collection's models column
+type model struct {
+	ID        int64           `json:"id"`
+	Name      string          `json:"name"`
+	Type      int             `json:"type"`
+	Mod       int64           `json:"mod"`
+	USN       int             `json:"usn"`
+	SortField int             `json:"sortf"`
+	DeckID    int64           `json:"did"`
+	Templates []template      `json:"tmpls"`
+	Fields    []field         `json:"flds"`
+	CSS       string          `json:"css"`
+	LatexPre  string          `json:"latexPre"`
+	LatexPost string          `json:"latexPost"`
+	Tags      []string        `json:"tags"`
+	Vers      []int           `json:"vers"`
+	Req       [][]interface{} `json:"req,omitempty"`
+}
+
+type template struct {
+	Name     string      `json:"name"`
+	Ord      int         `json:"ord"`
+	Question string      `json:"qfmt"`
+	Answer   string      `json:"afmt"`
+	DeckID   interface{} `json:"did"`
+	BQFmt    string      `json:"bqfmt"`
+	BAFmt    string      `json:"bafmt"`
+}
+
+type field struct {
+	Name   string   `json:"name"`
+	Ord    int      `json:"ord"`
+	Sticky bool     `json:"sticky"`
+	RTL    bool     `json:"rtl"`
+	Font   string   `json:"font"`
+	Size   int      `json:"size"`
+	Media  []string `json:"media"`
+}
+
+// deck is an entry in the collection's decks column
+type deck struct {
+	ID               int64  `json:"id"`
+	Name             string `json:"name"`
+	Mod              int64  `json:"mod"`
+	USN              int    `json:"usn"`
+	Description      string `json:"desc"`
+	Dyn              int    `json:"dyn"`
+	Conf             int    `json:"conf"`
+	Collapsed        bool   `json:"collapsed"`
+	BrowserCollapsed bool   `json:"browserCollapsed"`
+	NewToday         [2]int `json:"newToday"`
+	RevToday         [2]int `json:"revToday"`
+	LrnToday         [2]int `json:"lrnToday"`
+	TimeToday        [2]int `json:"timeToday"`
+	ExtendNew        int    `json:"extendNew"`
+	ExtendRev        int    `json:"extendRev"`
+}
+
+const latexPre = "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n"
+
+const latexPost = "\\end{document}"
+
+// noteTypes returns the basic and cloze note types. Both hold the Arabic text,
+// the translation and the reference; the cloze type hides parts of the matn.
+func noteTypes(deckID, mod int64) map[string]model {
+	fields := func(first string) []field {
+		return []field{
+			{Name: first, Ord: 0, RTL: true, Font: "Arial", Size: 30, Media: []string{}},
+			{Name: "Translation", Ord: 1, Font: "Arial", Size: 20, Media: []string{}},
+			{Name: "Reference", Ord: 2, Font: "Arial", Size: 14, Media: []string{}},
+		}
+	}
+	back := `<div class="translation">{{Translation}}</div><div class="reference">{{Reference}}</div>`
+
+	basic := model{
+		ID:     basicModelID,
+		Name:   "Hadith",
+		Type:   0,
+		Mod:    mod,
+		USN:    -1,
+		DeckID: deckID,
+		Templates: []template{{
+			Name:     "Recall",
+			Question: `<div class="arab" dir="rtl" lang="ar">{{Arabic}}</div>`,
+			Answer:   `{{FrontSide}}<hr id="answer">` + back,
+		}},
+		Fields:    fields("Arabic"),
+		CSS:       css,
+		LatexPre:  latexPre,
+		LatexPost: latexPost,
+		Tags:      []string{},
+		Vers:      []int{},
+		Req:       [][]interface{}{{0, "all", []int{0}}},
+	}
+
+	cloze := model{
+		ID:     clozeModelID,
+		Name:   "Hadith (cloze)",
+		Type:   1,
+		Mod:    mod,
+		USN:    -1,
+		DeckID: deckID,
+		Templates: []template{{
+			Name:     "Cloze",
+			Question: `<div class="arab" dir="rtl" lang="ar">{{cloze:Text}}</div>`,
+			Answer:   `<div class="arab" dir="rtl" lang="ar">{{cloze:Text}}</div><hr id="answer">` + back,
+		}},
+		Fields:    fields("Text"),
+		CSS:       css,
+		LatexPre:  latexPre,
+		LatexPost: latexPost,
+		Tags:      []string{},
+		Vers:      []int{},
+	}
+
+	return map[string]model{
+		strconv.FormatInt(basicModelID, 10): basic,
+		strconv.FormatInt(clozeModelID, 10): cloze,
+	}
+}
+
+// defaultDeck is the "Default" deck every collection has
+func defaultDeck(mod int64) deck {
+	return deck{ID: 1, Name: "Default", Mod: mod, Conf: 1}
+}
+
+// collectionConf is the collection's conf column
+const collectionConf = `{"activeDecks":[1],"curDeck":1,"newSpread":0,"collapseTime":1200,"timeLim":0,"estTimes":true,"dueCounts":true,"curModel":null,"nextPos":1,"sortType":"noteFld","sortBackwards":false,"addToCur":true}`
+
+// deckConf is the collection's dconf column, holding Anki's default scheduling options
+const deckConf = `{"1":{"id":1,"name":"Default","mod":0,"usn":0,"maxTaken":60,"autoplay":true,"timer":0,"replayq":true,"dyn":false,` +
+	`"new":{"bury":true,"delays":[1,10],"initialFactor":2500,"ints":[1,4,7],"order":1,"perDay":20,"separate":true},` +
+	`"lapse":{"delays":[10],"leechAction":0,"leechFails":8,"minInt":1,"mult":0},` +
+	`"rev":{"bury":true,"ease4":1.3,"fuzz":0.05,"ivlFct":1,"maxIvl":36500,"minSpace":1,"perDay":100}}}`
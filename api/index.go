@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -19,6 +20,26 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// The handler and its repository are kept across the requests a warm
+// instance serves, so that a database is opened and the offline bundle is
+// built once
+var (
+	handlerOnce   sync.Once
+	hadithHandler *handlers.HadithHandler
+	handlerErr    error
+)
+
+// openHandler opens the repository and creates the handler on the first request
+func openHandler(cfg *config.Config, dataDir string) (*handlers.HadithHandler, error) {
+	handlerOnce.Do(func() {
+		var repo repository.Repository
+		if repo, handlerErr = repository.Open(cfg, dataDir); handlerErr == nil {
+			hadithHandler = handlers.NewHadithHandler(repo)
+		}
+	})
+	return hadithHandler, handlerErr
+}
+
 // Handler is the serverless function entry point for Vercel
 func Handler(w http.ResponseWriter, r *http.Request) {
 	// Set GO_ENV to production for Vercel
@@ -105,11 +126,15 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set up the repository with the determined data directory
-	repo := repository.NewFileRepository(dataDir)
-
-	// Initialize the handlers with the repository
-	hadithHandler := handlers.NewHadithHandler(repo)
+	// Set up the handlers with the repository of the configured storage backend
+	hadithHandler, err := openHandler(cfg, dataDir)
+	if err != nil {
+		errorMsg := fmt.Sprintf("Could not open repository: %v", err)
+		log.Printf(errorMsg)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(errorMsg))
+		return
+	}
 
 	// Set up Gin router
 	gin.SetMode(gin.ReleaseMode)
@@ -0,0 +1,131 @@
+// Package booklet lays out a selection of hadiths as a printable PDF or an
+// EPUB, with right-to-left Arabic, the Indonesian translation, a table of
+// contents and a citation for every hadith.
+package booklet
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hadith-api/models"
+)
+
+// font is embedded in every booklet, see fonts/LICENSE
+//
+//go:embed fonts/DejaVuSans.ttf
+var font []byte
+
+// fontName is the family and file name under which the font is embedded
+const fontName = "DejaVuSans"
+
+// sectionSize is the number of hadiths per section when a collection has no book headings
+const sectionSize = 20
+
+// Entry is a hadith in a booklet together with its collection and citation
+type Entry struct {
+	Slug       string
+	Collection string
+	Citation   string
+	Hadith     models.Hadith
+}
+
+// Section is a run of entries listed in the table of contents
+type Section struct {
+	Title   string
+	Entries []Entry
+}
+
+// Booklet is a titled selection of hadiths divided into sections
+type Booklet struct {
+	Title    string
+	Subtitle string
+	Sections []Section
+}
+
+// New divides the entries into sections, starting a new section whenever the
+// collection or book changes. Entries without a book are split into sections
+// of sectionSize hadiths titled with their number range.
+func New(title, subtitle string, entries []Entry) *Booklet {
+	b := &Booklet{Title: title, Subtitle: subtitle}
+
+	var current []Entry
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		b.Sections = append(b.Sections, Section{Title: sectionTitle(current), Entries: current})
+		current = nil
+	}
+
+	for _, entry := range entries {
+		if n := len(current); n > 0 {
+			last := current[n-1]
+			if last.Slug != entry.Slug || last.Hadith.Book != entry.Hadith.Book ||
+				(entry.Hadith.Book == "" && n == sectionSize) {
+				flush()
+			}
+		}
+		current = append(current, entry)
+	}
+	flush()
+
+	return b
+}
+
+// sectionTitle names a section after its book, or its collection and number range
+func sectionTitle(entries []Entry) string {
+	first, last := entries[0], entries[len(entries)-1]
+	if first.Hadith.Book != "" {
+		return first.Hadith.Book
+	}
+	if len(entries) == 1 {
+		return fmt.Sprintf("%s, no. %s", first.Collection, first.Hadith.Number)
+	}
+	return fmt.Sprintf("%s, no. %s–%s", first.Collection, first.Hadith.Number, last.Hadith.Number)
+}
+
+// Len returns the number of hadiths in the booklet
+func (b *Booklet) Len() int {
+	n := 0
+	for _, section := range b.Sections {
+		n += len(section.Entries)
+	}
+	return n
+}
+
+// Format is an output format for booklets
+type Format struct {
+	ContentType string
+	Extension   string
+	Write       func(w io.Writer, b *Booklet) error
+}
+
+// Formats lists the supported booklet formats by extension
+var Formats = map[string]Format{
+	"pdf": {
+		ContentType: "application/pdf",
+		Extension:   "pdf",
+		Write:       WritePDF,
+	},
+	"epub": {
+		ContentType: "application/epub+zip",
+		Extension:   "epub",
+		Write:       WriteEPUB,
+	},
+}
+
+// Split separates a requested file name such as "malik.pdf" into its base
+// name and booklet format
+func Split(name string) (string, Format, bool) {
+	dot := strings.LastIndex(name, ".")
+	if dot < 0 {
+		return name, Format{}, false
+	}
+	format, ok := Formats[strings.ToLower(name[dot+1:])]
+	if !ok {
+		return name, Format{}, false
+	}
+	return name[:dot], format, true
+}
@@ -0,0 +1,89 @@
+package booklet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hadith-api/models"
+)
+
+func testEntries() []Entry {
+	return []Entry{
+		{Slug: "malik", Collection: "Muwatta Malik", Citation: "HR. Malik no. 1", Hadith: models.Hadith{
+			Number: models.Number{Value: 1}, Book: "Kitab ash-Shalah",
+			Arab: "إِنَّمَا الْأَعْمَالُ بِالنِّيَّاتِ", ID: "Sesungguhnya amal itu tergantung niatnya.",
+		}},
+		{Slug: "malik", Collection: "Muwatta Malik", Citation: "HR. Malik no. 2a", Hadith: models.Hadith{
+			Number: models.Number{Value: 2, Suffix: "a"}, Book: "Kitab ash-Shalah",
+			Arab: "قَالَ <الدِّينُ> & النَّصِيحَةُ", ID: "Agama itu nasihat, a < b & c.",
+		}},
+		{Slug: "darimi", Collection: "Sunan Darimi", Citation: "HR. Darimi no. 5", Hadith: models.Hadith{
+			Number: models.Number{Value: 5},
+			Arab:   "الطُّهُورُ شَطْرُ الْإِيمَانِ", ID: "Bersuci itu separuh dari iman.",
+		}},
+	}
+}
+
+func TestNew(t *testing.T) {
+	b := New("Pilihan", "No. 1–5", testEntries())
+	if b.Len() != 3 {
+		t.Errorf("Len = %d, want 3", b.Len())
+	}
+	// The collection changes after the book
+	if len(b.Sections) != 2 || b.Sections[0].Title != "Kitab ash-Shalah" {
+		t.Errorf("Sections = %+v, want the book and then Darimi", b.Sections)
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePDF(&buf, New("Pilihan", "No. 1–5", testEntries())); err != nil {
+		t.Fatal(err)
+	}
+
+	pdf := buf.Bytes()
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) || !bytes.Contains(pdf[len(pdf)-32:], []byte("%%EOF")) {
+		t.Errorf("output of %d bytes is not a complete PDF", len(pdf))
+	}
+}
+
+func TestWriteEPUB(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteEPUB(&buf, New("Pilihan", "No. 1–5", testEntries())); err != nil {
+		t.Fatal(err)
+	}
+
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if z.File[0].Name != "mimetype" || z.File[0].Method != zip.Store {
+		t.Errorf("first file is %s, want the stored mimetype", z.File[0].Name)
+	}
+
+	// Every document is well-formed, with the text escaped
+	for _, f := range z.File {
+		if !strings.HasSuffix(f.Name, ".xhtml") && !strings.HasSuffix(f.Name, ".opf") &&
+			!strings.HasSuffix(f.Name, ".ncx") && !strings.HasSuffix(f.Name, ".xml") {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := xml.NewDecoder(r)
+		for {
+			if _, err = d.Token(); err != nil {
+				break
+			}
+		}
+		r.Close()
+		if err != io.EOF {
+			t.Errorf("%s is not well-formed: %v", f.Name, err)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package booklet
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// epubTemplates holds the package documents of an EPUB 3 book. The navigation
+// is written both as an EPUB 3 nav document and as an NCX for older readers.
+// The XML declaration is written separately, as html/template would escape it.
+var epubTemplates = template.Must(template.New("container").Funcs(template.FuncMap{
+	"file": sectionFile,
+	"inc":  func(i int) int { return i + 1 },
+}).Parse(`<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+{{define "package"}}<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" xml:lang="id">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="book-id">{{.ID}}</dc:identifier>
+    <dc:title>{{.Booklet.Title}}</dc:title>
+    <dc:language>id</dc:language>
+    <dc:language>ar</dc:language>
+    <dc:publisher>Hadith API</dc:publisher>
+    <meta property="dcterms:modified">{{.Modified}}</meta>
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+    <item id="style" href="style.css" media-type="text/css"/>
+    <item id="font" href="fonts/{{.Font}}.ttf" media-type="font/ttf"/>
+    <item id="title" href="title.xhtml" media-type="application/xhtml+xml"/>
+{{- range $i, $s := .Booklet.Sections}}
+    <item id="section-{{$i}}" href="{{file $i}}" media-type="application/xhtml+xml"/>
+{{- end}}
+  </manifest>
+  <spine toc="ncx">
+    <itemref idref="title"/>
+    <itemref idref="nav"/>
+{{- range $i, $s := .Booklet.Sections}}
+    <itemref idref="section-{{$i}}"/>
+{{- end}}
+  </spine>
+</package>
+{{end}}
+{{define "ncx"}}<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+  <head>
+    <meta name="dtb:uid" content="{{.ID}}"/>
+  </head>
+  <docTitle><text>{{.Booklet.Title}}</text></docTitle>
+  <navMap>
+{{- range $i, $s := .Booklet.Sections}}
+    <navPoint id="nav-{{$i}}" playOrder="{{inc $i}}">
+      <navLabel><text>{{$s.Title}}</text></navLabel>
+      <content src="{{file $i}}"/>
+    </navPoint>
+{{- end}}
+  </navMap>
+</ncx>
+{{end}}
+{{define "nav"}}<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" lang="id" xml:lang="id">
+<head>
+  <title>Daftar Isi</title>
+  <link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+  <nav epub:type="toc" id="toc">
+    <h1>Daftar Isi</h1>
+    <ol>
+{{- range $i, $s := .Booklet.Sections}}
+      <li><a href="{{file $i}}">{{$s.Title}}</a></li>
+{{- end}}
+    </ol>
+  </nav>
+</body>
+</html>
+{{end}}
+{{define "title"}}<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" lang="id" xml:lang="id">
+<head>
+  <title>{{.Booklet.Title}}</title>
+  <link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body class="title">
+  <h1>{{.Booklet.Title}}</h1>
+{{- if .Booklet.Subtitle}}
+  <p class="subtitle">{{.Booklet.Subtitle}}</p>
+{{- end}}
+  <p class="size">{{.Booklet.Len}} hadits</p>
+</body>
+</html>
+{{end}}
+{{define "section"}}<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" lang="id" xml:lang="id">
+<head>
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+  <h2>{{.Title}}</h2>
+{{- range .Entries}}
+  <section class="hadith" id="{{.Slug}}-{{.Hadith.Number}}">
+    <p class="arab" dir="rtl" lang="ar" xml:lang="ar">{{.Hadith.Arab}}</p>
+    <p class="translation">{{.Hadith.ID}}</p>
+    <footer class="citation">{{.Citation}}</footer>
+  </section>
+{{- end}}
+</body>
+</html>
+{{end}}`))
+
+// epubStyle embeds the font and sets the Arabic text right to left
+var epubStyle = fmt.Sprintf(`@font-face {
+  font-family: "%[1]s";
+  src: url(fonts/%[1]s.ttf);
+}
+body { font-family: serif; line-height: 1.5; }
+h2 { border-bottom: 1px solid #999; }
+.title { text-align: center; margin-top: 30%%; }
+.subtitle, .size, .citation { color: #666; }
+.hadith { margin: 1.5em 0; padding-bottom: 1em; border-bottom: 1px solid #ddd; }
+.arab { font-family: "%[1]s", serif; font-size: 1.4em; line-height: 2; direction: rtl; text-align: right; }
+.translation { text-align: justify; }
+.citation { font-size: 0.85em; }
+`, fontName)
+
+// epubData is passed to the package templates
+type epubData struct {
+	ID       string
+	Font     string
+	Modified string
+	Booklet  *Booklet
+}
+
+// epubFile is a document in the book rendered from one of the templates
+type epubFile struct {
+	name     string
+	template string
+	data     interface{}
+}
+
+// WriteEPUB writes the booklet as an EPUB 3 book with the font embedded.
+// Each section is a separate document and every hadith ends with its citation.
+func WriteEPUB(w io.Writer, b *Booklet) error {
+	z := zip.NewWriter(w)
+
+	// The mimetype must come first and be stored uncompressed
+	mimetype, err := z.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mimetype, "application/epub+zip"); err != nil {
+		return err
+	}
+
+	data := epubData{
+		ID:       bookID(b),
+		Font:     fontName,
+		Modified: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		Booklet:  b,
+	}
+
+	files := []epubFile{
+		{"META-INF/container.xml", "container", nil},
+		{"OEBPS/content.opf", "package", data},
+		{"OEBPS/toc.ncx", "ncx", data},
+		{"OEBPS/nav.xhtml", "nav", data},
+		{"OEBPS/title.xhtml", "title", data},
+	}
+	for i, section := range b.Sections {
+		files = append(files, epubFile{"OEBPS/" + sectionFile(i), "section", section})
+	}
+
+	for _, file := range files {
+		f, err := z.Create(file.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, xml.Header); err != nil {
+			return err
+		}
+		if err := epubTemplates.ExecuteTemplate(f, file.template, file.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	if err := writeFile(z, "OEBPS/style.css", []byte(epubStyle)); err != nil {
+		return err
+	}
+	if err := writeFile(z, "OEBPS/fonts/"+fontName+".ttf", font); err != nil {
+		return err
+	}
+
+	return z.Close()
+}
+
+func writeFile(z *zip.Writer, name string, content []byte) error {
+	f, err := z.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(content)
+	return err
+}
+
+// sectionFile names the document of the i-th section
+func sectionFile(i int) string {
+	return fmt.Sprintf("section-%03d.xhtml", i+1)
+}
+
+// bookID derives a stable identifier from the hadiths in the booklet, so the
+// same selection is recognised as the same book by reading apps
+func bookID(b *Booklet) string {
+	h := sha1.New()
+	for _, section := range b.Sections {
+		for _, entry := range section.Entries {
+			fmt.Fprintf(h, "%s:%s\n", entry.Slug, entry.Hadith.Number)
+		}
+	}
+	return fmt.Sprintf("urn:hadith-api:%x", h.Sum(nil)[:8])
+}
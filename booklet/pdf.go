@@ -0,0 +1,300 @@
+package booklet
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/hadith-api/shaping"
+)
+
+// Page layout in millimetres on A4 paper
+const (
+	pageMargin    = 20.0
+	footerMargin  = 25.0
+	tocHeading    = 20.0
+	tocLine       = 7.0
+	arabicSize    = 15.0
+	arabicLine    = 9.0
+	textSize      = 10.5
+	textLine      = 5.5
+	minEntrySpace = 35.0
+)
+
+// pdfLayout writes a booklet page by page, remembering which hadiths appear
+// on each page for the citation footer
+type pdfLayout struct {
+	pdf       *fpdf.Fpdf
+	citations map[int][]string
+	// current is the citation of the hadith being written, which may run over several pages
+	current string
+}
+
+// tocEntry is where a section starts, for the table of contents
+type tocEntry struct {
+	link int
+	page int
+}
+
+// WritePDF writes the booklet as an A4 PDF with the font embedded. The table
+// of contents is written last, onto pages reserved after the title page,
+// once the page of every section is known.
+func WritePDF(w io.Writer, b *Booklet) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, footerMargin)
+	pdf.AddUTF8FontFromBytes(fontName, "", font)
+	pdf.SetTitle(b.Title, true)
+	pdf.SetCreator("Hadith API", true)
+
+	l := &pdfLayout{pdf: pdf, citations: make(map[int][]string)}
+	pdf.SetFooterFunc(l.footer)
+
+	l.titlePage(b)
+
+	tocStart := pdf.PageNo() + 1
+	for i := 0; i < l.tocPages(len(b.Sections)); i++ {
+		pdf.AddPage()
+	}
+
+	entries := make([]tocEntry, len(b.Sections))
+	for i, section := range b.Sections {
+		entries[i] = l.section(section, i == 0)
+	}
+
+	last := pdf.PageNo()
+	l.contents(b.Sections, entries, tocStart)
+	pdf.SetPage(last)
+
+	return pdf.Output(w)
+}
+
+// titlePage writes the booklet's title, subtitle and size
+func (l *pdfLayout) titlePage(b *Booklet) {
+	pdf := l.pdf
+	pdf.AddPage()
+	_, pageHeight := pdf.GetPageSize()
+
+	pdf.SetY(pageHeight / 3)
+	pdf.SetFont(fontName, "", 24)
+	pdf.MultiCell(0, 11, visual(b.Title), "", "C", false)
+	pdf.Ln(4)
+
+	pdf.SetFont(fontName, "", 13)
+	if b.Subtitle != "" {
+		pdf.MultiCell(0, 7, visual(b.Subtitle), "", "C", false)
+	}
+	pdf.SetTextColor(110, 110, 110)
+	pdf.CellFormat(0, 7, fmt.Sprintf("%d hadits", b.Len()), "", 1, "C", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+}
+
+// tocPages returns the number of pages needed to list the sections
+func (l *pdfLayout) tocPages(sections int) int {
+	_, pageHeight := l.pdf.GetPageSize()
+	perPage := int((pageHeight - pageMargin - footerMargin) / tocLine)
+	firstPage := int((pageHeight - pageMargin - footerMargin - tocHeading) / tocLine)
+
+	if sections <= firstPage {
+		return 1
+	}
+	return 1 + (sections-firstPage+perPage-1)/perPage
+}
+
+// contents writes the table of contents onto the reserved pages. Page breaks
+// are made by hand, as automatic breaks would append pages at the end.
+func (l *pdfLayout) contents(sections []Section, entries []tocEntry, page int) {
+	pdf := l.pdf
+	pageWidth, pageHeight := pdf.GetPageSize()
+	width := pageWidth - 2*pageMargin
+
+	pdf.SetAutoPageBreak(false, footerMargin)
+	pdf.SetPage(page)
+	pdf.SetXY(pageMargin, pageMargin)
+	pdf.SetFont(fontName, "", 16)
+	pdf.CellFormat(0, tocHeading-6, "Daftar Isi", "", 1, "L", false, 0, "")
+	pdf.Ln(6)
+
+	pdf.SetFont(fontName, "", 11)
+	for i, section := range sections {
+		if pdf.GetY()+tocLine > pageHeight-footerMargin {
+			page++
+			pdf.SetPage(page)
+			pdf.SetXY(pageMargin, pageMargin)
+		}
+
+		number := fmt.Sprint(entries[i].page)
+		numberWidth := pdf.GetStringWidth(number) + 4
+		title := l.fit(visual(section.Title), width-numberWidth)
+
+		pdf.CellFormat(width-numberWidth, tocLine, title, "", 0, "L", false, entries[i].link, "")
+		pdf.CellFormat(numberWidth, tocLine, number, "", 1, "R", false, entries[i].link, "")
+	}
+
+	pdf.SetAutoPageBreak(true, footerMargin)
+}
+
+// section writes a section heading followed by its hadiths. The first
+// section, and any that would start near the bottom of a page, begin a new page.
+func (l *pdfLayout) section(section Section, first bool) tocEntry {
+	pdf := l.pdf
+	_, pageHeight := pdf.GetPageSize()
+
+	if first || pdf.GetY()+minEntrySpace+12 > pageHeight-footerMargin {
+		pdf.AddPage()
+	} else {
+		pdf.Ln(6)
+	}
+
+	entry := tocEntry{link: pdf.AddLink(), page: pdf.PageNo()}
+	pdf.SetLink(entry.link, pdf.GetY(), entry.page)
+	pdf.Bookmark(section.Title, 0, pdf.GetY())
+
+	pdf.SetFont(fontName, "", 14)
+	pdf.MultiCell(0, 8, visual(section.Title), "B", alignment(section.Title), false)
+	pdf.Ln(4)
+
+	for _, e := range section.Entries {
+		l.entry(e)
+	}
+	return entry
+}
+
+// entry writes a hadith: its citation, the Arabic text right to left and the translation
+func (l *pdfLayout) entry(e Entry) {
+	pdf := l.pdf
+	pageWidth, pageHeight := pdf.GetPageSize()
+	width := pageWidth - 2*pageMargin
+
+	if pdf.GetY()+minEntrySpace > pageHeight-footerMargin {
+		pdf.AddPage()
+	}
+	start := pdf.PageNo()
+	l.citations[start] = append(l.citations[start], e.Citation)
+	l.current = e.Citation
+
+	pdf.SetFont(fontName, "", 9)
+	pdf.SetTextColor(110, 110, 110)
+	pdf.CellFormat(0, 5, e.Citation, "", 1, "L", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(1)
+
+	pdf.SetFont(fontName, "", arabicSize)
+	for _, line := range l.arabicLines(e.Hadith.Arab, width) {
+		pdf.CellFormat(0, arabicLine, line, "", 1, "R", false, 0, "")
+	}
+	pdf.Ln(2)
+
+	pdf.SetFont(fontName, "", textSize)
+	pdf.MultiCell(0, textLine, e.Hadith.ID, "", "J", false)
+	pdf.Ln(3)
+
+	pdf.SetDrawColor(200, 200, 200)
+	pdf.Line(pageMargin, pdf.GetY(), pageWidth-pageMargin, pdf.GetY())
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.Ln(4)
+
+	if end := pdf.PageNo(); end != start {
+		l.citations[end] = append(l.citations[end], e.Citation)
+	}
+	l.current = ""
+}
+
+// arabicLines wraps Arabic text to the width and returns each line in visual order.
+// Words are measured in their shaped form, as that is what is drawn.
+func (l *pdfLayout) arabicLines(text string, width float64) []string {
+	pdf := l.pdf
+	space := pdf.GetStringWidth(" ")
+
+	var lines []string
+	var line []string
+	var lineWidth float64
+	for _, word := range strings.Fields(text) {
+		wordWidth := pdf.GetStringWidth(shaping.Visual(word))
+		if len(line) > 0 && lineWidth+space+wordWidth > width {
+			lines = append(lines, shaping.Visual(strings.Join(line, " ")))
+			line, lineWidth = nil, 0
+		}
+		if len(line) > 0 {
+			lineWidth += space
+		}
+		line = append(line, word)
+		lineWidth += wordWidth
+	}
+	if len(line) > 0 {
+		lines = append(lines, shaping.Visual(strings.Join(line, " ")))
+	}
+
+	return lines
+}
+
+// footer writes the citations of the hadiths on the page and the page number.
+// The title page has no footer.
+func (l *pdfLayout) footer() {
+	pdf := l.pdf
+	page := pdf.PageNo()
+	if page == 1 {
+		return
+	}
+
+	citations := l.citations[page]
+	if l.current != "" && (len(citations) == 0 || citations[len(citations)-1] != l.current) {
+		citations = append(citations, l.current)
+	}
+
+	pageWidth, _ := pdf.GetPageSize()
+	width := pageWidth - 2*pageMargin
+	number := fmt.Sprint(page)
+
+	pdf.SetY(-pageMargin + 2)
+	pdf.SetFont(fontName, "", 8)
+	pdf.SetTextColor(110, 110, 110)
+	numberWidth := pdf.GetStringWidth(number) + 4
+
+	pdf.CellFormat(width-numberWidth, 5, l.footnote(citations, width-numberWidth), "T", 0, "L", false, 0, "")
+	pdf.CellFormat(numberWidth, 5, number, "T", 0, "R", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+}
+
+// footnote joins citations into a line of the given width, shortening it to
+// the first and last citation when they do not all fit
+func (l *pdfLayout) footnote(citations []string, width float64) string {
+	if len(citations) == 0 {
+		return ""
+	}
+
+	line := strings.Join(citations, "; ")
+	if l.pdf.GetStringWidth(line) <= width {
+		return line
+	}
+	return l.fit(citations[0]+" … "+citations[len(citations)-1], width)
+}
+
+// fit shortens text with an ellipsis until it fits the width
+func (l *pdfLayout) fit(text string, width float64) string {
+	if l.pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && l.pdf.GetStringWidth(string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// visual prepares text for drawing, shaping it when it is Arabic
+func visual(text string) string {
+	if shaping.IsArabic(text) {
+		return shaping.Visual(text)
+	}
+	return text
+}
+
+// alignment aligns Arabic text to the right and anything else to the left
+func alignment(text string) string {
+	if shaping.IsArabic(text) {
+		return "R"
+	}
+	return "L"
+}
@@ -0,0 +1,219 @@
+// Package bundle compiles the corpus into a single SQLite file for offline
+// clients, with collection metadata and a full-text index that mirrors the
+// API's search.
+package bundle
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/normalize"
+	_ "modernc.org/sqlite"
+)
+
+// SchemaVersion is the version of the bundle's table layout. It is raised
+// whenever the layout changes, so clients can tell which queries a bundle supports.
+const SchemaVersion = 1
+
+// ContentType is the media type of a bundle
+const ContentType = "application/vnd.sqlite3"
+
+// schema creates the bundle's tables. The search table is contentless: it
+// only holds the index, keyed by the rowid of the hadith. Its text is indexed
+// in the form of normalize.SearchText, like that of the SQLite repository, so
+// clients search it with queries normalized the same way.
+const schema = `
+CREATE TABLE schema_version (
+	version    INTEGER NOT NULL,
+	dataset    TEXT NOT NULL,
+	revision   INTEGER NOT NULL,
+	created_at TEXT NOT NULL
+);
+CREATE TABLE collections (
+	slug          TEXT PRIMARY KEY,
+	name          TEXT NOT NULL,
+	title         TEXT NOT NULL,
+	compiler      TEXT NOT NULL,
+	total_hadiths INTEGER NOT NULL,
+	grades        TEXT NOT NULL,
+	schemes       TEXT NOT NULL,
+	aliases       TEXT NOT NULL
+);
+CREATE TABLE hadiths (
+	id          INTEGER PRIMARY KEY,
+	slug        TEXT NOT NULL REFERENCES collections (slug),
+	number      TEXT NOT NULL,
+	position    INTEGER NOT NULL,
+	arab        TEXT NOT NULL,
+	translation TEXT NOT NULL,
+	grades      TEXT,
+	tags        TEXT,
+	book        TEXT,
+	chapter     TEXT,
+	hash        TEXT NOT NULL,
+	UNIQUE (slug, number)
+);
+CREATE INDEX hadiths_position ON hadiths (slug, position);
+CREATE VIRTUAL TABLE hadith_search USING fts5 (
+	arab,
+	translation,
+	content = '',
+	tokenize = 'unicode61 remove_diacritics 2'
+);
+`
+
+// Source provides the collections and hadiths a bundle is compiled from
+type Source interface {
+	GetAvailableNarrators() ([]string, error)
+	GetCollection(narrator string) (*models.Collection, error)
+	GetHadithsByNarrator(narrator string, params models.QueryParams) ([]models.Hadith, int, error)
+	GetRevision() (*models.Revision, error)
+}
+
+// Info describes a compiled bundle
+type Info struct {
+	SchemaVersion int       `json:"schema_version"`
+	Dataset       string    `json:"dataset"`
+	Revision      int       `json:"revision"`
+	CreatedAt     time.Time `json:"created_at"`
+	Collections   int       `json:"collections"`
+	Hadiths       int       `json:"hadiths"`
+}
+
+// Build writes every collection of the source into a new SQLite file at path,
+// replacing any existing file. The dataset version is a hash of the content,
+// so bundles built from the same data carry the same version.
+func Build(path string, src Source) (*Info, error) {
+	narrators, err := src.GetAvailableNarrators()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(narrators)
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer db.Close()
+
+	// The file is written once in full, so it needs no journal
+	if _, err := db.Exec(`PRAGMA journal_mode = OFF; PRAGMA synchronous = OFF;`); err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("failed to create bundle: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	collectionStmt, err := tx.Prepare(`INSERT INTO collections VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return nil, err
+	}
+	hadithStmt, err := tx.Prepare(`INSERT INTO hadiths VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return nil, err
+	}
+	searchStmt, err := tx.Prepare(`INSERT INTO hadith_search (rowid, arab, translation) VALUES (?, ?, ?)`)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := src.GetRevision()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{SchemaVersion: SchemaVersion, Revision: revision.Revision, CreatedAt: time.Now().UTC()}
+	dataset := sha256.New()
+	var id int64
+
+	for _, narrator := range narrators {
+		collection, err := src.GetCollection(narrator)
+		if err != nil {
+			return nil, err
+		}
+		hadiths, _, err := src.GetHadithsByNarrator(narrator, models.QueryParams{})
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := collectionStmt.Exec(collection.Slug, collection.Name, collection.Title, collection.Compiler,
+			collection.TotalHadiths, jsonText(collection.Grades), jsonText(collection.Schemes),
+			jsonText(collection.Aliases)); err != nil {
+			return nil, fmt.Errorf("failed to write collection %s: %w", narrator, err)
+		}
+		info.Collections++
+
+		for position, h := range hadiths {
+			id++
+			if _, err := hadithStmt.Exec(id, narrator, h.Number.String(), position+1, h.Arab, h.ID,
+				optionalJSON(len(h.Grades) > 0, h.Grades), optionalJSON(len(h.Tags) > 0, h.Tags),
+				nullable(h.Book), nullable(h.Chapter), h.Hash); err != nil {
+				return nil, fmt.Errorf("failed to write hadith %s:%s: %w", narrator, h.Number, err)
+			}
+			if _, err := searchStmt.Exec(id, normalize.SearchText(h.Arab), normalize.SearchText(h.ID)); err != nil {
+				return nil, fmt.Errorf("failed to index hadith %s:%s: %w", narrator, h.Number, err)
+			}
+
+			fmt.Fprintf(dataset, "%s\x00%s\x00", narrator, jsonText(h))
+			info.Hadiths++
+		}
+	}
+
+	info.Dataset = hex.EncodeToString(dataset.Sum(nil))[:16]
+	if _, err := tx.Exec(`INSERT INTO schema_version VALUES (?, ?, ?, ?)`,
+		SchemaVersion, info.Dataset, info.Revision, info.CreatedAt.Format(time.RFC3339)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// Merge the index into a single segment and compact the file for download
+	if _, err := db.Exec(`INSERT INTO hadith_search (hadith_search) VALUES ('optimize')`); err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(fmt.Sprintf(`PRAGMA user_version = %d; VACUUM;`, SchemaVersion)); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// jsonText encodes v as JSON text
+func jsonText(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// optionalJSON encodes v as JSON text, or NULL when it is empty
+func optionalJSON(present bool, v interface{}) interface{} {
+	if !present {
+		return nil
+	}
+	return jsonText(v)
+}
+
+// nullable stores empty strings as NULL
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
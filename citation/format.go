@@ -0,0 +1,122 @@
+package citation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hadith-api/models"
+)
+
+// Supported citation styles
+const (
+	// StyleHR is the Indonesian "HR." (hadits riwayat) convention, e.g. "HR. Malik no. 12"
+	StyleHR = "hr"
+	// StyleAcademic is an English academic reference with compiler, title and number
+	StyleAcademic = "academic"
+	// StyleBibTeX is a BibTeX @misc entry
+	StyleBibTeX = "bibtex"
+	// StyleCSL is a CSL-JSON item for citation managers such as Zotero
+	StyleCSL = "csl-json"
+)
+
+// Styles lists the supported citation styles
+var Styles = []string{StyleHR, StyleAcademic, StyleBibTeX, StyleCSL}
+
+// CSLName is a CSL-JSON name variable
+type CSLName struct {
+	Literal string `json:"literal"`
+}
+
+// CSLItem is a CSL-JSON item describing a single hadith
+type CSLItem struct {
+	ID             string    `json:"id"`
+	Type           string    `json:"type"`
+	Title          string    `json:"title"`
+	ContainerTitle string    `json:"container-title"`
+	Author         []CSLName `json:"author,omitempty"`
+	Number         string    `json:"number"`
+	URL            string    `json:"URL,omitempty"`
+}
+
+// Format produces a citation of a hadith in the given style, using the
+// collection's name, title and compiler. url is the hadith's API address and
+// may be empty.
+func Format(style string, collection *models.Collection, number models.Number, url string) (*models.Citation, error) {
+	name := collection.Name
+	if name == "" {
+		name = collection.Slug
+	}
+	title := collection.Title
+	if title == "" {
+		title = name
+	}
+
+	var result interface{}
+	switch style {
+	case StyleHR:
+		result = fmt.Sprintf("HR. %s no. %s", name, number)
+
+	case StyleAcademic:
+		var b strings.Builder
+		if collection.Compiler != "" {
+			b.WriteString(collection.Compiler + ", ")
+		}
+		fmt.Fprintf(&b, "%s, hadith no. %s", title, number)
+		if url != "" {
+			fmt.Fprintf(&b, ", %s", url)
+		}
+		b.WriteString(".")
+		result = b.String()
+
+	case StyleBibTeX:
+		var b strings.Builder
+		fmt.Fprintf(&b, "@misc{%s_%s,\n", collection.Slug, number)
+		if collection.Compiler != "" {
+			fmt.Fprintf(&b, "  author = {%s},\n", escapeBibTeX(collection.Compiler))
+		}
+		fmt.Fprintf(&b, "  title = {{%s}, Hadith No. %s},\n", escapeBibTeX(title), number)
+		if url != "" {
+			fmt.Fprintf(&b, "  howpublished = {\\url{%s}},\n", url)
+		}
+		b.WriteString("}")
+		result = b.String()
+
+	case StyleCSL:
+		item := CSLItem{
+			ID:             fmt.Sprintf("%s-%s", collection.Slug, number),
+			Type:           "entry",
+			Title:          fmt.Sprintf("%s, hadith no. %s", title, number),
+			ContainerTitle: title,
+			Number:         number.String(),
+			URL:            url,
+		}
+		if collection.Compiler != "" {
+			item.Author = []CSLName{{Literal: collection.Compiler}}
+		}
+		result = item
+
+	default:
+		return nil, fmt.Errorf("unsupported citation style %q, expected one of %s", style, strings.Join(Styles, ", "))
+	}
+
+	return &models.Citation{
+		Style:    style,
+		Citation: result,
+	}, nil
+}
+
+// bibtexEscaper escapes characters with a special meaning in BibTeX
+var bibtexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	"{", `\{`,
+	"}", `\}`,
+	"&", `\&`,
+	"%", `\%`,
+	"$", `\$`,
+	"#", `\#`,
+	"_", `\_`,
+)
+
+func escapeBibTeX(s string) string {
+	return bibtexEscaper.Replace(s)
+}
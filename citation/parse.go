@@ -0,0 +1,203 @@
+// Package citation parses free-text hadith citations such as "HR. Malik no. 12"
+// and matches the cited collection names onto collection slugs
+package citation
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/normalize"
+)
+
+// Reference is a hadith citation parsed from free text
+type Reference struct {
+	// Collection is the normalized collection name as written, e.g. "sunan darimi"
+	Collection string
+	// Number is the cited hadith number
+	Number models.Number
+	// Book is set when the citation has a "book/number" form such as "1/23"
+	Book int
+}
+
+// CollectionMatch is a collection slug matched against the cited name
+type CollectionMatch struct {
+	Slug  string
+	Score float64
+}
+
+var (
+	// bookNumberPattern matches "1/23" and "1:23" style references
+	bookNumberPattern = regexp.MustCompile(`(\d+)\s*[/:]\s*(\d+[a-z]?)\b`)
+	// numberPattern matches a plain hadith number, optionally with a sub-letter
+	numberPattern = regexp.MustCompile(`\b(\d+[a-z]?)\b`)
+)
+
+// stopwords are the words surrounding a citation that don't name the collection
+var stopwords = map[string]bool{
+	// Indonesian
+	"hr": true, "h": true, "r": true, "riwayat": true, "diriwayatkan": true, "oleh": true,
+	"no": true, "nomor": true, "nomer": true, "hadis": true, "hadits": true, "kitab": true, "juz": true,
+	"dan": true, "dalam": true,
+	// English
+	"narrated": true, "reported": true, "by": true, "number": true, "num": true,
+	"hadith": true, "hadeeth": true, "book": true, "vol": true, "volume": true, "in": true, "of": true,
+	// Arabic
+	"رواه": true, "اخرجه": true, "رقم": true, "حديث": true, "في": true, "ج": true,
+}
+
+// ErrNoNumber is returned when a citation contains no hadith number
+var ErrNoNumber = errors.New("citation does not contain a hadith number")
+
+// Parse extracts the collection name and hadith number from a free-text citation.
+// Arabic-Indic digits are accepted, and a "book/number" form is split into its parts.
+func Parse(text string) (Reference, error) {
+	normalized := normalize.Text(text)
+
+	var ref Reference
+	var err error
+
+	if m := bookNumberPattern.FindStringSubmatchIndex(normalized); m != nil {
+		ref.Book, _ = strconv.Atoi(normalized[m[2]:m[3]])
+		ref.Number, err = models.ParseNumber(normalized[m[4]:m[5]])
+		normalized = normalized[:m[0]] + " " + normalized[m[1]:]
+	} else if m := lastMatch(numberPattern, normalized); m != nil {
+		ref.Number, err = models.ParseNumber(normalized[m[2]:m[3]])
+		normalized = normalized[:m[0]] + " " + normalized[m[1]:]
+	} else {
+		return Reference{}, ErrNoNumber
+	}
+	if err != nil {
+		return Reference{}, err
+	}
+
+	var words []string
+	for _, word := range normalize.Tokenize(normalized) {
+		if !stopwords[word] && !isNumeric(word) {
+			words = append(words, word)
+		}
+	}
+	ref.Collection = strings.Join(words, " ")
+
+	return ref, nil
+}
+
+// MatchCollections ranks the given collection slugs by how well their aliases
+// match the cited collection name. Collections that don't match are omitted;
+// when no name was cited, every collection is returned with a low score.
+func MatchCollections(name string, slugs []string, aliases map[string][]string) []CollectionMatch {
+	var matches []CollectionMatch
+
+	for _, slug := range slugs {
+		var score float64
+		if name == "" {
+			score = 0.1
+		} else {
+			candidates := append([]string{slug}, aliases[slug]...)
+			for _, alias := range candidates {
+				if s := aliasScore(name, strings.Join(normalize.Tokenize(alias), " ")); s > score {
+					score = s
+				}
+			}
+		}
+
+		if score > 0 {
+			matches = append(matches, CollectionMatch{Slug: slug, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches
+}
+
+// aliasScore scores a cited name against a single normalized alias
+func aliasScore(name, alias string) float64 {
+	if name == alias {
+		return 1
+	}
+
+	nameWords := strings.Fields(name)
+	aliasWords := strings.Fields(alias)
+
+	// The alias appears within a longer name, e.g. "kitab sunan darimi"
+	if containsAll(nameWords, aliasWords) {
+		return 0.9
+	}
+	// The name is a shortened form of the alias, e.g. "sunan"
+	if containsAll(aliasWords, nameWords) {
+		return 0.5 * float64(len(nameWords)) / float64(len(aliasWords))
+	}
+
+	// Tolerate small spelling differences such as "muwattha"
+	if ratio := similarity(name, alias); ratio >= 0.75 {
+		return 0.8 * ratio
+	}
+	return 0
+}
+
+// containsAll reports whether every word in subset appears in words
+func containsAll(words, subset []string) bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	for _, w := range subset {
+		if !set[w] {
+			return false
+		}
+	}
+	return len(subset) > 0
+}
+
+// similarity returns 1 minus the normalized Levenshtein distance between a and b
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return 1 - float64(prev[len(rb)])/float64(longest)
+}
+
+// lastMatch returns the submatch indices of the last match of pattern in s
+func lastMatch(pattern *regexp.Regexp, s string) []int {
+	all := pattern.FindAllStringSubmatchIndex(s, -1)
+	if len(all) == 0 {
+		return nil
+	}
+	return all[len(all)-1]
+}
+
+// isNumeric reports whether s consists only of ASCII digits
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,61 @@
+package citation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hadith-api/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Reference
+	}{
+		// Indonesian
+		{"HR. Malik no. 12", Reference{Collection: "malik", Number: models.Number{Value: 12}}},
+		{"Diriwayatkan oleh Ad-Darimi nomor 1234", Reference{Collection: "ad darimi", Number: models.Number{Value: 1234}}},
+		{"HR. Sunan Darimi, juz 2, no. 12a", Reference{Collection: "sunan darimi", Number: models.Number{Value: 12, Suffix: "a"}}},
+		// English
+		{"Narrated by Malik, hadith number 45", Reference{Collection: "malik", Number: models.Number{Value: 45}}},
+		{"Muwatta Malik, Book 1, Hadith 7B", Reference{Collection: "muwatta malik", Number: models.Number{Value: 7, Suffix: "b"}}},
+		// Book and number
+		{"Malik 1/23", Reference{Collection: "malik", Number: models.Number{Value: 23}, Book: 1}},
+		{"Darimi 2 : 15", Reference{Collection: "darimi", Number: models.Number{Value: 15}, Book: 2}},
+		// Arabic, with Arabic-Indic and Persian digits
+		{"رواه مالك رقم ١٢", Reference{Collection: "مالك", Number: models.Number{Value: 12}}},
+		{"أخرجه الدارمي في سننه ۴۵", Reference{Collection: "الدارمي سننه", Number: models.Number{Value: 45}}},
+		{"رواه مالك ٣/٤٥", Reference{Collection: "مالك", Number: models.Number{Value: 45}, Book: 3}},
+		// Without a collection name
+		{"no. 5", Reference{Number: models.Number{Value: 5}}},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want error
+	}{
+		{"HR. Malik", ErrNoNumber},
+		{"رواه البخاري", ErrNoNumber},
+		{"", ErrNoNumber},
+	}
+
+	for _, tt := range tests {
+		if _, err := Parse(tt.in); !errors.Is(err, tt.want) {
+			t.Errorf("Parse(%q) error = %v, want %v", tt.in, err, tt.want)
+		}
+	}
+
+	if _, err := Parse("Malik no. 0"); err == nil {
+		t.Error("Parse(Malik no. 0): want an invalid number error")
+	}
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"flag"
+	"log"
+
+	"github.com/hadith-api/bundle"
+	"github.com/hadith-api/repository"
+)
+
+// runBundle compiles every collection into a single SQLite file for offline clients
+func runBundle(args []string) error {
+	flags := flag.NewFlagSet("bundle", flag.ExitOnError)
+	dataDir := flags.String("data", "./api/data", "Data directory containing the collection files")
+	out := flags.String("out", "hadith.sqlite", "Output file, replaced if it exists")
+	flags.Parse(args)
+
+	repo := repository.NewFileRepository(*dataDir)
+	info, err := bundle.Build(*out, repo)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Written %d hadiths from %d collections to %s (schema %d, dataset %s)",
+		info.Hadiths, info.Collections, *out, info.SchemaVersion, info.Dataset)
+	return nil
+}
@@ -0,0 +1,80 @@
+// Package cli implements the command-line tools that run through the main
+// binary instead of the server, e.g. `go run main.go tag`
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// command is a subcommand of the main binary
+type command struct {
+	description string
+	run         func(args []string) error
+}
+
+// commands lists the available subcommands by name
+var commands = map[string]command{
+	"bundle": {
+		description: "Compile all collections into a versioned SQLite file with a full-text index",
+		run:         runBundle,
+	},
+	"build-static": {
+		description: "Pre-render the API's JSON responses with compressed variants for static hosting",
+		run:         runBuildStatic,
+	},
+	"cluster": {
+		description: "Group hadiths into thematic clusters and write them to the meta directory",
+		run:         runCluster,
+	},
+	"migrate": {
+		description: "Create or update the SQLite or PostgreSQL database and import the collection files into it",
+		run:         runMigrate,
+	},
+	"ngrams": {
+		description: "Report the most frequent n-grams and collocations of a collection or the corpus",
+		run:         runNGrams,
+	},
+	"revision": {
+		description: "Record the current data as a new dataset revision for incremental sync",
+		run:         runRevision,
+	},
+	"tag": {
+		description: "Assign topics to hadiths with keyword rules and write sidecar tag files for review",
+		run:         runTag,
+	},
+	"tei-export": {
+		description: "Export a collection or a range of it as TEI XML",
+		run:         runTEIExport,
+	},
+	"tei-import": {
+		description: "Convert a TEI XML document into a collection file",
+		run:         runTEIImport,
+	},
+}
+
+// Run executes the named command with its arguments
+func Run(name string, args []string) error {
+	cmd, ok := commands[name]
+	if !ok {
+		usage()
+		return fmt.Errorf("unknown command %q", name)
+	}
+	return cmd.run(args)
+}
+
+// usage prints the available commands
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "Usage: hadith-api [command] [flags]")
+	fmt.Fprintln(os.Stderr, "\nWithout a command the API server is started. Commands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", name, commands[name].description)
+	}
+}
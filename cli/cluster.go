@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hadith-api/clustering"
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/repository"
+)
+
+// runCluster groups the hadiths of all narrators into thematic clusters and
+// writes the assignments with the top keywords of each cluster to the meta directory
+func runCluster(args []string) error {
+	opts := clustering.DefaultOptions
+
+	flags := flag.NewFlagSet("cluster", flag.ExitOnError)
+	dataDir := flags.String("data", "./api/data", "Data directory containing the collection files")
+	out := flags.String("out", "", "Output file (default: <data>/meta/clusters.json)")
+	field := flags.String("field", "id", "Text to cluster on: id (translation), arab or both")
+	flags.IntVar(&opts.K, "k", opts.K, "Number of clusters")
+	flags.IntVar(&opts.MaxIterations, "iterations", opts.MaxIterations, "Maximum number of k-means iterations")
+	flags.IntVar(&opts.Keywords, "keywords", opts.Keywords, "Number of top keywords per cluster")
+	flags.IntVar(&opts.MinDocs, "min-docs", opts.MinDocs, "Ignore words found in fewer hadiths")
+	flags.Float64Var(&opts.MaxShare, "max-share", opts.MaxShare, "Ignore words found in more than this share of hadiths")
+	flags.Int64Var(&opts.Seed, "seed", opts.Seed, "Random seed for the initial centroids")
+	flags.Parse(args)
+
+	if *out == "" {
+		*out = filepath.Join(*dataDir, "meta", "clusters.json")
+	}
+
+	var text func(h models.Hadith) string
+	switch *field {
+	case "id":
+		text = func(h models.Hadith) string { return h.ID }
+	case "arab":
+		text = func(h models.Hadith) string { return h.Arab }
+	case "both":
+		text = func(h models.Hadith) string { return h.Arab + " " + h.ID }
+	default:
+		return fmt.Errorf("unknown field %q, expected id, arab or both", *field)
+	}
+
+	repo := repository.NewFileRepository(*dataDir)
+	refs, _, err := repo.GetCorpus()
+	if err != nil {
+		return err
+	}
+
+	texts := make([]string, len(refs))
+	for i, ref := range refs {
+		h, err := repo.GetHadithByNumber(ref.Slug, ref.Number)
+		if err != nil {
+			return err
+		}
+		texts[i] = text(*h)
+	}
+
+	// Narrator names outside the brackets would otherwise lead the keywords
+	names := clustering.Names(texts)
+	docs := make([][]string, len(texts))
+	for i, t := range texts {
+		docs[i] = clustering.Terms(t, names)
+	}
+
+	log.Printf("Clustering %d hadiths into %d clusters", len(docs), opts.K)
+	clusters := clustering.Run(docs, opts)
+
+	set := &models.ClusterSet{
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Field:       *field,
+		K:           opts.K,
+		Seed:        opts.Seed,
+		Clusters:    make([]models.Cluster, len(clusters)),
+	}
+	for i, cluster := range clusters {
+		hadiths := make(map[string][]models.Number)
+		for _, member := range cluster.Members {
+			ref := refs[member]
+			hadiths[ref.Slug] = append(hadiths[ref.Slug], ref.Number)
+		}
+
+		set.Clusters[i] = models.Cluster{
+			ID:           i + 1,
+			TotalHadiths: len(cluster.Members),
+			Keywords:     cluster.Keywords,
+			Hadiths:      hadiths,
+		}
+		log.Printf("  %d (%d hadiths): %s", i+1, len(cluster.Members), strings.Join(cluster.Keywords, ", "))
+	}
+
+	if err := repository.WriteClusterFile(*out, set); err != nil {
+		return err
+	}
+
+	log.Printf("Written %d clusters to %s", len(set.Clusters), *out)
+	return nil
+}
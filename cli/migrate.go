@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/hadith-api/repository"
+)
+
+// runMigrate brings the database up to date and imports the collection files
+// into it: the SQLite database, or the PostgreSQL database when a URL is given
+func runMigrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dataDir := flags.String("data", "./api/data", "Data directory containing the collection files")
+	path := flags.String("db", "./hadith.db", "SQLite database, created if it does not exist")
+	url := flags.String("url", "", "PostgreSQL connection URL, to migrate PostgreSQL instead of SQLite")
+	flags.Parse(args)
+
+	files := repository.NewFileRepository(*dataDir)
+
+	if *url != "" {
+		ctx := context.Background()
+		version, err := repository.MigratePostgres(ctx, *url)
+		if err != nil {
+			return err
+		}
+
+		repo, err := repository.NewPostgresRepository(ctx, *dataDir, *url)
+		if err != nil {
+			return err
+		}
+		defer repo.Close()
+
+		total, err := repo.Import(ctx, files)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Imported %d hadiths into PostgreSQL (schema version %d)", total, version)
+		return nil
+	}
+
+	version, err := repository.MigrateSQLite(*path)
+	if err != nil {
+		return err
+	}
+
+	repo, err := repository.NewSQLiteRepository(*dataDir, *path)
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	total, err := repo.Import(files)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Imported %d hadiths into %s (schema version %d)", total, *path, version)
+	return nil
+}
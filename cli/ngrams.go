@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/ngram"
+	"github.com/hadith-api/repository"
+)
+
+// runNGrams prints the most frequent n-grams and collocations of a collection or the whole corpus
+func runNGrams(args []string) error {
+	opts := ngram.DefaultOptions
+
+	flags := flag.NewFlagSet("ngrams", flag.ExitOnError)
+	dataDir := flags.String("data", "./api/data", "Data directory containing the collection files")
+	narrator := flags.String("narrator", "", "Only analyze this narrator (default: the whole corpus)")
+	asJSON := flags.Bool("json", false, "Print the report as JSON")
+	flags.StringVar(&opts.Field, "field", opts.Field, "Text to analyze: arab or id")
+	flags.IntVar(&opts.MinN, "min-n", opts.MinN, "Shortest n-gram length")
+	flags.IntVar(&opts.MaxN, "max-n", opts.MaxN, "Longest n-gram length")
+	flags.IntVar(&opts.MinCount, "min-count", opts.MinCount, "Ignore n-grams occurring fewer times")
+	flags.IntVar(&opts.Top, "top", opts.Top, "Number of n-grams and collocations to report")
+	flags.StringVar(&opts.Measure, "measure", opts.Measure, "Collocation measure: pmi or llr")
+	flags.Parse(args)
+
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	repo := repository.NewFileRepository(*dataDir)
+	narrators := []string{*narrator}
+	if *narrator == "" {
+		var err error
+		if narrators, err = repo.GetAvailableNarrators(); err != nil {
+			return err
+		}
+	}
+
+	var docs [][]string
+	for _, narrator := range narrators {
+		hadiths, _, err := repo.GetHadithsByNarrator(narrator, models.QueryParams{})
+		if err != nil {
+			return err
+		}
+		for _, h := range hadiths {
+			docs = append(docs, ngram.Tokens(h, opts.Field))
+		}
+	}
+
+	report := ngram.Analyze(docs, opts)
+	report.Narrator = *narrator
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "N-GRAM\tN\tCOUNT\n")
+	for _, gram := range report.NGrams {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", gram.Words, gram.N, gram.Count)
+	}
+	fmt.Fprintf(w, "\nCOLLOCATION\tCOUNT\tPMI\tLOG-LIKELIHOOD\n")
+	for _, col := range report.Collocations {
+		fmt.Fprintf(w, "%s\t%d\t%.3f\t%.3f\n", col.Words, col.Count, col.PMI, col.LogLikelihood)
+	}
+	return w.Flush()
+}
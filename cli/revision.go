@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"flag"
+	"log"
+
+	"github.com/hadith-api/repository"
+)
+
+// runRevision records the current data as a new dataset revision when it has changed
+func runRevision(args []string) error {
+	flags := flag.NewFlagSet("revision", flag.ExitOnError)
+	dataDir := flags.String("data", "./api/data", "Data directory containing the collection files")
+	flags.Parse(args)
+
+	repo := repository.NewFileRepository(*dataDir)
+
+	revision, err := repo.RecordRevision()
+	if err != nil {
+		return err
+	}
+	if revision == nil {
+		current, err := repo.GetRevision()
+		if err != nil {
+			return err
+		}
+		log.Printf("Data unchanged since revision %d", current.Revision)
+		return nil
+	}
+
+	previous := revision.Revision - 1
+	changes, err := repo.GetChanges(previous)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Recorded revision %d with %d hadiths: %d added, %d modified, %d removed since revision %d",
+		revision.Revision, revision.TotalHadiths, len(changes.Added), len(changes.Modified), len(changes.Removed), previous)
+	return nil
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"flag"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/handlers"
+	"github.com/hadith-api/repository"
+	"github.com/hadith-api/routes"
+	"github.com/hadith-api/static"
+)
+
+// runBuildStatic pre-renders the API's JSON responses for static hosting
+func runBuildStatic(args []string) error {
+	flags := flag.NewFlagSet("build-static", flag.ExitOnError)
+	dataDir := flags.String("data", "./api/data", "Data directory containing the collection files")
+	out := flags.String("out", "./static-api", "Output directory, replaced if it holds a previous build")
+	flags.Parse(args)
+
+	repo := repository.NewFileRepository(*dataDir)
+
+	// Render through the same routes as the server, so the files match its URLs
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	routes.SetupHadithRoutes(router.Group("/api/v1"), handlers.NewHadithHandler(repo))
+
+	manifest, err := static.Build(*out, router, "/api/v1", repo)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Written %d responses to %s (revision %d); %d routes are left to the API: %v",
+		len(manifest.Files), *out, manifest.Revision, len(manifest.Dynamic), manifest.Dynamic)
+	return nil
+}
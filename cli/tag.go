@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/repository"
+	"github.com/hadith-api/tagging"
+)
+
+// runTag tags every hadith in the data directory and writes one sidecar tag
+// file per narrator to the output directory. Hand-curated tags (those without
+// a confidence) are carried over, so the output can replace meta/topics once reviewed.
+func runTag(args []string) error {
+	flags := flag.NewFlagSet("tag", flag.ExitOnError)
+	dataDir := flags.String("data", "./api/data", "Data directory containing the collection files")
+	rulesPath := flags.String("rules", "", "Tagging rules file (default: <data>/meta/tagging_rules.json)")
+	outDir := flags.String("out", "./tagging-output", "Directory to write the sidecar tag files to")
+	threshold := flags.Float64("threshold", -1, "Minimum confidence, overriding the rules file")
+	narrator := flags.String("narrator", "", "Only tag this narrator")
+	flags.Parse(args)
+
+	if *rulesPath == "" {
+		*rulesPath = filepath.Join(*dataDir, "meta", "tagging_rules.json")
+	}
+
+	rules, err := tagging.LoadRules(*rulesPath)
+	if err != nil {
+		return err
+	}
+	if *threshold >= 0 {
+		rules.Threshold = *threshold
+	}
+	tagger := tagging.NewTagger(rules)
+
+	repo := repository.NewFileRepository(*dataDir)
+	narrators := []string{*narrator}
+	if *narrator == "" {
+		if narrators, err = repo.GetAvailableNarrators(); err != nil {
+			return err
+		}
+	}
+
+	for _, narrator := range narrators {
+		hadiths, _, err := repo.GetHadithsByNarrator(narrator, models.QueryParams{})
+		if err != nil {
+			return err
+		}
+
+		tags := make(map[models.Number][]models.Tag)
+		topicCounts := make(map[string]int)
+		for _, h := range hadiths {
+			// Keep hand-curated tags and replace earlier automatic ones
+			var hadithTags []models.Tag
+			for _, tag := range h.Tags {
+				if tag.Confidence == 0 {
+					hadithTags = append(hadithTags, tag)
+				}
+			}
+
+			curated := models.Hadith{Tags: hadithTags}
+			for _, tag := range tagger.Tag(h) {
+				if !curated.HasTopic(tag.Topic) {
+					hadithTags = append(hadithTags, tag)
+				}
+			}
+
+			if len(hadithTags) > 0 {
+				tags[h.Number] = hadithTags
+				for _, tag := range hadithTags {
+					topicCounts[tag.Topic]++
+				}
+			}
+		}
+
+		path := filepath.Join(*outDir, fmt.Sprintf("%s.json", narrator))
+		if err := repository.WriteTagFile(path, tags); err != nil {
+			return err
+		}
+
+		log.Printf("Tagged %d of %d hadiths for %s, written to %s", len(tags), len(hadiths), narrator, path)
+		topics := make([]string, 0, len(topicCounts))
+		for topic := range topicCounts {
+			topics = append(topics, topic)
+		}
+		sort.Strings(topics)
+		for _, topic := range topics {
+			log.Printf("  %s: %d", topic, topicCounts[topic])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/repository"
+	"github.com/hadith-api/tei"
+)
+
+// runTEIExport writes a collection, or a range of it, as a TEI XML document
+func runTEIExport(args []string) error {
+	flags := flag.NewFlagSet("tei-export", flag.ExitOnError)
+	dataDir := flags.String("data", "./api/data", "Data directory containing the collection files")
+	narrator := flags.String("narrator", "", "Narrator whose collection to export (required)")
+	from := flags.String("from", "", "First hadith number to export")
+	to := flags.String("to", "", "Last hadith number to export")
+	out := flags.String("out", "", "Output file (default: standard output)")
+	flags.Parse(args)
+
+	if *narrator == "" {
+		return fmt.Errorf("-narrator is required")
+	}
+
+	repo := repository.NewFileRepository(*dataDir)
+	collection, err := repo.GetCollectionInfo(*narrator)
+	if err != nil {
+		return err
+	}
+
+	hadiths, _, err := repo.GetHadithsByNarrator(*narrator, models.QueryParams{})
+	if err != nil {
+		return err
+	}
+	if (*from != "" || *to != "") && len(hadiths) > 0 {
+		first, last := hadiths[0].Number, hadiths[len(hadiths)-1].Number
+		if *from != "" {
+			if first, err = models.ParseNumber(*from); err != nil {
+				return err
+			}
+		}
+		if *to != "" {
+			if last, err = models.ParseNumber(*to); err != nil {
+				return err
+			}
+		}
+		if hadiths, err = repo.GetHadithRange(*narrator, first, last); err != nil {
+			return err
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", *out, err)
+		}
+		defer file.Close()
+		w = file
+	}
+	buffered := bufio.NewWriter(w)
+
+	writer, err := tei.NewWriter(buffered, tei.Header{
+		Slug:   collection.Slug,
+		Title:  collection.Title,
+		Author: collection.Compiler,
+	})
+	if err != nil {
+		return err
+	}
+	for _, h := range hadiths {
+		if err := writer.WriteHadith(h); err != nil {
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+
+	if *out != "" {
+		log.Printf("Exported %d hadiths of %s to %s", len(hadiths), *narrator, *out)
+	}
+	return nil
+}
+
+// runTEIImport converts a TEI XML document into a collection file in the data file format
+func runTEIImport(args []string) error {
+	flags := flag.NewFlagSet("tei-import", flag.ExitOnError)
+	in := flags.String("in", "", "TEI document to import (required)")
+	out := flags.String("out", "", "Collection file to write, e.g. api/data/<slug>.json (default: standard output)")
+	flags.Parse(args)
+
+	if *in == "" {
+		return fmt.Errorf("-in is required")
+	}
+
+	file, err := os.Open(*in)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", *in, err)
+	}
+	defer file.Close()
+
+	hadiths, err := tei.Import(file)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		outFile, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", *out, err)
+		}
+		defer outFile.Close()
+		w = outFile
+	}
+
+	// Collection files are compact JSON with the Arabic and markup characters unescaped
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(hadiths); err != nil {
+		return fmt.Errorf("failed to write hadiths: %w", err)
+	}
+
+	if *out != "" {
+		log.Printf("Imported %d hadiths from %s to %s", len(hadiths), *in, *out)
+	}
+	return nil
+}
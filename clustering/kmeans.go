@@ -0,0 +1,358 @@
+// Package clustering groups hadiths into emergent themes with spherical
+// k-means over TF-IDF vectors of their normalized words
+package clustering
+
+import (
+	"math"
+	"math/rand"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/hadith-api/normalize"
+)
+
+// Options controls the vectorization and the k-means run
+type Options struct {
+	// K is the number of clusters
+	K int
+	// MaxIterations bounds the number of k-means iterations
+	MaxIterations int
+	// Keywords is the number of top keywords reported per cluster
+	Keywords int
+	// MinDocs drops words occurring in fewer documents
+	MinDocs int
+	// MaxShare drops words occurring in more than this share of documents
+	MaxShare float64
+	// Seed makes the initial centroids, and thus the result, reproducible
+	Seed int64
+}
+
+// DefaultOptions are the options used by the cluster command
+var DefaultOptions = Options{
+	K:             20,
+	MaxIterations: 50,
+	Keywords:      10,
+	MinDocs:       3,
+	MaxShare:      0.25,
+	Seed:          1,
+}
+
+// Cluster is a group of documents with the words that characterize it
+type Cluster struct {
+	// Members are the indexes of the documents in the cluster
+	Members  []int
+	Keywords []string
+}
+
+// brackets matches the narrator names the Indonesian translations put in square brackets
+var brackets = regexp.MustCompile(`\[[^\]]*\]`)
+
+const (
+	// minNameCount is the number of times a word must be bracketed to be taken for a name
+	minNameCount = 2
+	// minNameShare is the share of a word's occurrences that must be bracketed
+	// for it to be taken for a name
+	minNameShare = 0.15
+)
+
+// Names returns the words of the texts that are narrator names. The
+// translations bracket only some narrators of an isnad, so the names are
+// learnt from the brackets and then left out wherever they occur: a word is
+// a name when enough of its occurrences are bracketed.
+func Names(texts []string) map[string]bool {
+	bracketed := make(map[string]int)
+	total := make(map[string]int)
+	for _, text := range texts {
+		for _, span := range brackets.FindAllString(text, -1) {
+			for _, word := range normalize.Tokenize(span) {
+				bracketed[word]++
+			}
+		}
+		for _, word := range normalize.Tokenize(text) {
+			total[word]++
+		}
+	}
+
+	names := make(map[string]bool)
+	for word, count := range bracketed {
+		if count >= minNameCount && float64(count) >= minNameShare*float64(total[word]) {
+			names[word] = true
+		}
+	}
+	return names
+}
+
+// Terms returns the words of a text used for clustering: normalized tokens
+// without the bracketed narrator names or any of names, stopwords, digits
+// and very short words
+func Terms(text string, names map[string]bool) []string {
+	var terms []string
+	for _, word := range normalize.Tokenize(brackets.ReplaceAllString(text, " ")) {
+		if utf8.RuneCountInString(word) < 3 || normalize.IsStopword(word) || names[word] {
+			continue
+		}
+		if word[0] >= '0' && word[0] <= '9' {
+			continue
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// entry is a non-zero dimension of a sparse vector
+type entry struct {
+	dim    int
+	weight float64
+}
+
+// vector is a sparse, unit-length TF-IDF vector ordered by dimension, so that
+// sums are computed in the same order on every run
+type vector []entry
+
+// Run clusters documents, each given as its list of terms. Clusters are
+// returned largest first; documents without any usable term are left out.
+func Run(docs [][]string, opts Options) []Cluster {
+	vocabulary, vectors := vectorize(docs, opts)
+
+	// Only documents with a non-empty vector take part
+	var indexes []int
+	for i, v := range vectors {
+		if len(v) > 0 {
+			indexes = append(indexes, i)
+		}
+	}
+
+	k := opts.K
+	if k > len(indexes) {
+		k = len(indexes)
+	}
+	if k < 1 {
+		return []Cluster{}
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	centroids := initCentroids(vectors, indexes, k, len(vocabulary), rng)
+	assignment := make(map[int]int, len(indexes))
+
+	for iteration := 0; iteration < opts.MaxIterations; iteration++ {
+		changed := false
+		for _, i := range indexes {
+			best, bestScore := 0, -1.0
+			for c, centroid := range centroids {
+				if score := dot(vectors[i], centroid); score > bestScore {
+					best, bestScore = c, score
+				}
+			}
+			if previous, ok := assignment[i]; !ok || previous != best {
+				assignment[i] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+		centroids = updateCentroids(vectors, indexes, assignment, centroids)
+	}
+
+	clusters := make([]Cluster, k)
+	for _, i := range indexes {
+		c := assignment[i]
+		clusters[c].Members = append(clusters[c].Members, i)
+	}
+	for c := range clusters {
+		clusters[c].Keywords = topTerms(centroids[c], vocabulary, opts.Keywords)
+	}
+
+	// Drop clusters that ended up empty and order by size
+	result := clusters[:0]
+	for _, cluster := range clusters {
+		if len(cluster.Members) > 0 {
+			result = append(result, cluster)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return len(result[i].Members) > len(result[j].Members) })
+
+	return result
+}
+
+// vectorize builds the vocabulary and a TF-IDF vector per document
+func vectorize(docs [][]string, opts Options) ([]string, []vector) {
+	docFreq := make(map[string]int)
+	for _, terms := range docs {
+		seen := make(map[string]bool)
+		for _, term := range terms {
+			if !seen[term] {
+				seen[term] = true
+				docFreq[term]++
+			}
+		}
+	}
+
+	maxDocs := int(opts.MaxShare * float64(len(docs)))
+	var vocabulary []string
+	for term, n := range docFreq {
+		if n >= opts.MinDocs && (opts.MaxShare <= 0 || n <= maxDocs) {
+			vocabulary = append(vocabulary, term)
+		}
+	}
+	sort.Strings(vocabulary)
+
+	index := make(map[string]int, len(vocabulary))
+	idf := make([]float64, len(vocabulary))
+	for i, term := range vocabulary {
+		index[term] = i
+		idf[i] = math.Log(float64(len(docs)) / float64(docFreq[term]))
+	}
+
+	vectors := make([]vector, len(docs))
+	for d, terms := range docs {
+		counts := make(map[int]float64)
+		for _, term := range terms {
+			if i, ok := index[term]; ok {
+				counts[i]++
+			}
+		}
+
+		v := make(vector, 0, len(counts))
+		for i, tf := range counts {
+			v = append(v, entry{dim: i, weight: (1 + math.Log(tf)) * idf[i]})
+		}
+		sort.Slice(v, func(i, j int) bool { return v[i].dim < v[j].dim })
+		normalizeSparse(v)
+		vectors[d] = v
+	}
+
+	return vocabulary, vectors
+}
+
+// initCentroids picks k starting centroids with k-means++ seeding
+func initCentroids(vectors []vector, indexes []int, k, dims int, rng *rand.Rand) [][]float64 {
+	centroids := [][]float64{dense(vectors[indexes[rng.Intn(len(indexes))]], dims)}
+
+	distances := make([]float64, len(indexes))
+	for i := range distances {
+		distances[i] = math.Inf(1)
+	}
+
+	for len(centroids) < k {
+		last := centroids[len(centroids)-1]
+		total := 0.0
+		for j, i := range indexes {
+			// Cosine distance of unit vectors
+			if d := 1 - dot(vectors[i], last); d < distances[j] {
+				distances[j] = d
+			}
+			total += distances[j] * distances[j]
+		}
+
+		// Draw the next centroid with a probability proportional to the
+		// squared distance from the nearest one picked so far
+		pick := indexes[rng.Intn(len(indexes))]
+		if total > 0 {
+			target := rng.Float64() * total
+			for j, i := range indexes {
+				target -= distances[j] * distances[j]
+				if target <= 0 {
+					pick = i
+					break
+				}
+			}
+		}
+		centroids = append(centroids, dense(vectors[pick], dims))
+	}
+
+	return centroids
+}
+
+// updateCentroids recomputes each centroid as the normalized mean of its members.
+// A cluster that lost all its members keeps its previous centroid.
+func updateCentroids(vectors []vector, indexes []int, assignment map[int]int, previous [][]float64) [][]float64 {
+	centroids := make([][]float64, len(previous))
+	for c := range centroids {
+		centroids[c] = make([]float64, len(previous[c]))
+	}
+
+	sizes := make([]int, len(previous))
+	for _, i := range indexes {
+		c := assignment[i]
+		sizes[c]++
+		for _, e := range vectors[i] {
+			centroids[c][e.dim] += e.weight
+		}
+	}
+
+	for c := range centroids {
+		if sizes[c] == 0 {
+			centroids[c] = previous[c]
+			continue
+		}
+		normalizeDense(centroids[c])
+	}
+
+	return centroids
+}
+
+// topTerms returns the n terms with the largest weight in a centroid
+func topTerms(centroid []float64, vocabulary []string, n int) []string {
+	dims := make([]int, 0, len(centroid))
+	for dim, w := range centroid {
+		if w > 0 {
+			dims = append(dims, dim)
+		}
+	}
+	sort.SliceStable(dims, func(i, j int) bool { return centroid[dims[i]] > centroid[dims[j]] })
+	if len(dims) > n {
+		dims = dims[:n]
+	}
+
+	terms := make([]string, len(dims))
+	for i, dim := range dims {
+		terms[i] = vocabulary[dim]
+	}
+	return terms
+}
+
+func dot(v vector, centroid []float64) float64 {
+	sum := 0.0
+	for _, e := range v {
+		sum += e.weight * centroid[e.dim]
+	}
+	return sum
+}
+
+func dense(v vector, dims int) []float64 {
+	d := make([]float64, dims)
+	for _, e := range v {
+		d[e.dim] = e.weight
+	}
+	return d
+}
+
+func normalizeSparse(v vector) {
+	norm := 0.0
+	for _, e := range v {
+		norm += e.weight * e.weight
+	}
+	if norm == 0 {
+		return
+	}
+	norm = math.Sqrt(norm)
+	for i := range v {
+		v[i].weight /= norm
+	}
+}
+
+func normalizeDense(v []float64) {
+	norm := 0.0
+	for _, w := range v {
+		norm += w * w
+	}
+	if norm == 0 {
+		return
+	}
+	norm = math.Sqrt(norm)
+	for dim := range v {
+		v[dim] /= norm
+	}
+}
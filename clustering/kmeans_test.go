@@ -0,0 +1,109 @@
+package clustering
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// corpus holds three themes of four documents each, indexes 0-3, 4-7 and 8-11
+var corpus = []string{
+	"shalat rakaat masjid subuh",
+	"shalat rakaat masjid maghrib berjamaah",
+	"shalat rakaat masjid isya",
+	"shalat rakaat masjid imam berjamaah",
+	"puasa ramadhan berbuka kurma",
+	"puasa ramadhan berbuka sahur",
+	"puasa ramadhan berbuka kurma sahur",
+	"puasa ramadhan berbuka senin",
+	"menjual emas perak riba tunai",
+	"menjual emas perak riba",
+	"menjual emas perak riba dijual",
+	"menjual emas perak riba buah",
+}
+
+func TestRun(t *testing.T) {
+	docs := make([][]string, len(corpus))
+	for i, text := range corpus {
+		docs[i] = Terms(text, nil)
+	}
+	// Words found in a single document are dropped, as in DefaultOptions
+	opts := Options{K: 3, MaxIterations: 50, Keywords: 2, MinDocs: 2, MaxShare: 0.5}
+
+	want := [][]int{{0, 1, 2, 3}, {4, 5, 6, 7}, {8, 9, 10, 11}}
+	keywords := map[int][]string{
+		0: {"masjid", "rakaat", "shalat", "berjamaah"},
+		4: {"puasa", "ramadhan", "berbuka", "kurma", "sahur"},
+		8: {"riba", "emas", "perak", "menjual"},
+	}
+
+	for seed := int64(1); seed <= 10; seed++ {
+		opts.Seed = seed
+		clusters := Run(docs, opts)
+
+		var got [][]int
+		for _, cluster := range clusters {
+			got = append(got, cluster.Members)
+			if len(cluster.Keywords) != opts.Keywords {
+				t.Errorf("seed %d: keywords %v, want %d", seed, cluster.Keywords, opts.Keywords)
+			}
+			for _, keyword := range cluster.Keywords {
+				if !contains(keywords[cluster.Members[0]], keyword) {
+					t.Errorf("seed %d: keyword %q of cluster %v is not one of %v", seed, keyword, cluster.Members, keywords[cluster.Members[0]])
+				}
+			}
+		}
+		sort.Slice(got, func(i, j int) bool { return got[i][0] < got[j][0] })
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("seed %d: clusters = %v, want %v", seed, got, want)
+		}
+
+		// The same seed gives the same result
+		if again := Run(docs, opts); !reflect.DeepEqual(again, clusters) {
+			t.Errorf("seed %d: second run = %+v, want %+v", seed, again, clusters)
+		}
+	}
+}
+
+func TestRunEmpty(t *testing.T) {
+	clusters := Run([][]string{nil, {}}, DefaultOptions)
+	if len(clusters) != 0 {
+		t.Errorf("Run of documents without terms = %+v, want none", clusters)
+	}
+}
+
+func TestNames(t *testing.T) {
+	texts := []string{
+		"Telah menceritakan kepada kami [Yahya] dari [Malik] dari [Nafi'] dari Ibnu Umar",
+		"[Yahya] menceritakan dari [Malik], bahwa budak itu dimerdekakan",
+		"Malik berkata tentang budak dan [Umar]",
+		"budak budak budak budak budak budak budak [budak]",
+	}
+
+	names := Names(texts)
+	for _, word := range []string{"yahya", "malik"} {
+		if !names[word] {
+			t.Errorf("Names: %q is missing", word)
+		}
+	}
+	// Bracketed only once, or mostly found outside brackets
+	for _, word := range []string{"umar", "budak", "nafi"} {
+		if names[word] {
+			t.Errorf("Names: %q is not a name", word)
+		}
+	}
+
+	got := Terms("Malik berkata tentang budak dan [Umar] serta Yahya", names)
+	if want := []string{"budak", "serta"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Terms = %v, want %v", got, want)
+	}
+}
+
+func contains(words []string, word string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
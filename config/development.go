@@ -7,5 +7,10 @@ func GetDevelopmentConfig() *Config {
 		DataDir:     "./api/data",
 		Port:        "8080",
 		BaseURL:     "http://localhost:8080",
+
+		DailyRepeatWindow: 365,
+
+		Storage:      "file",
+		DatabasePath: "./hadith.db",
 	}
 }
@@ -28,5 +28,10 @@ func GetProductionConfig() *Config {
 		DataDir:     dataDir,
 		Port:        port,
 		BaseURL:     baseURL,
+
+		DailyRepeatWindow: 365,
+
+		Storage:      "file",
+		DatabasePath: filepath.Join(execDir, "hadith.db"),
 	}
 }
@@ -0,0 +1,94 @@
+package daily
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hadith-api/hijri"
+	"github.com/hadith-api/models"
+)
+
+// MatchOccasion returns the first occasion that applies on the given date, or nil
+func MatchOccasion(occasions []models.Occasion, date time.Time, hijriDate hijri.Date) *models.Occasion {
+	for i := range occasions {
+		if matches(&occasions[i], date, hijriDate) {
+			return &occasions[i]
+		}
+	}
+	return nil
+}
+
+// matches reports whether every rule set on the occasion holds on the given date
+func matches(o *models.Occasion, date time.Time, hijriDate hijri.Date) bool {
+	if o.HijriMonth == 0 && o.Weekday == "" {
+		return false
+	}
+	if o.HijriMonth != 0 && o.HijriMonth != hijriDate.Month {
+		return false
+	}
+	if len(o.HijriDays) == 2 && (hijriDate.Day < o.HijriDays[0] || hijriDate.Day > o.HijriDays[1]) {
+		return false
+	}
+	if o.Weekday != "" && !strings.EqualFold(o.Weekday, date.Weekday().String()) {
+		return false
+	}
+	return true
+}
+
+// PickOccasion picks the hadith of the day from an occasion's list. Each Hijri
+// year the list is shuffled anew, and consecutive days (or weeks, for weekday
+// occasions) walk through the shuffled list so they don't repeat until it is
+// exhausted. Narrators for which available returns false are skipped.
+func PickOccasion(o *models.Occasion, date time.Time, hijriDate hijri.Date, fingerprint uint64, available func(string) bool) (models.HadithReference, bool) {
+	slugs := make([]string, 0, len(o.Hadiths))
+	for slug := range o.Hadiths {
+		if available(slug) {
+			slugs = append(slugs, slug)
+		}
+	}
+	sort.Strings(slugs)
+
+	var refs []models.HadithReference
+	for _, slug := range slugs {
+		for _, number := range o.Hadiths[slug] {
+			refs = append(refs, models.HadithReference{Slug: slug, Number: number})
+		}
+	}
+	if len(refs) == 0 {
+		return models.HadithReference{}, false
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(o.Name))
+	seed := fingerprint ^ hash.Sum64() ^ uint64(hijriDate.Year)
+
+	// Position within the occasion: days within the Hijri month, or weeks for weekday occasions
+	position := hijriDate.Day - 1
+	if o.HijriMonth == 0 {
+		position = DayNumber(date) / 7
+		if position < 0 {
+			position = -position
+		}
+	}
+
+	order := permutation(seed, len(refs))
+	return refs[order[position%len(refs)]], true
+}
+
+// permutation returns a deterministic shuffle of 0..n-1 for the given seed
+func permutation(seed uint64, n int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+
+	state := seed
+	for i := n - 1; i > 0; i-- {
+		state = splitmix64(state)
+		j := int(state % uint64(i+1))
+		order[i], order[j] = order[j], order[i]
+	}
+	return order
+}
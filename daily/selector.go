@@ -0,0 +1,117 @@
+// Package daily selects a deterministic "hadith of the day" from the corpus
+package daily
+
+import (
+	"sync"
+	"time"
+
+	// Embed the time zone database so ?tz= works on hosts without zoneinfo
+	_ "time/tzdata"
+)
+
+// Epoch is the first day of the selection sequence. Repeat avoidance applies
+// to days from the epoch onwards; earlier days are selected independently.
+var Epoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Selector picks one corpus index per day. The pick depends only on the date,
+// the corpus size and the corpus fingerprint, so every client sees the same
+// hadith on the same date. No index is picked twice within the repeat window.
+type Selector struct {
+	window int
+
+	mu          sync.Mutex
+	fingerprint uint64
+	size        int
+	picks       []int
+}
+
+// NewSelector creates a selector that avoids repeating a pick within window days
+func NewSelector(window int) *Selector {
+	if window < 0 {
+		window = 0
+	}
+	return &Selector{window: window}
+}
+
+// Pick returns the corpus index selected for the given date, for a corpus of
+// the given size and fingerprint
+func (s *Selector) Pick(date time.Time, size int, fingerprint uint64) int {
+	if size <= 0 {
+		return -1
+	}
+
+	day := DayNumber(date)
+	if day < 0 {
+		return draw(fingerprint, day, size, nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Start over when the corpus changes
+	if fingerprint != s.fingerprint || size != s.size {
+		s.fingerprint = fingerprint
+		s.size = size
+		s.picks = nil
+	}
+
+	window := s.window
+	if window > size-1 {
+		window = size - 1
+	}
+
+	// Extend the sequence up to the requested day, keeping track of the picks
+	// within the window so they are skipped
+	recent := make(map[int]int)
+	start := len(s.picks) - window
+	if start < 0 {
+		start = 0
+	}
+	for _, pick := range s.picks[start:] {
+		recent[pick]++
+	}
+
+	for d := len(s.picks); d <= day; d++ {
+		pick := draw(fingerprint, d, size, recent)
+		s.picks = append(s.picks, pick)
+
+		if window == 0 {
+			continue
+		}
+		recent[pick]++
+		if expired := d - window; expired >= 0 {
+			old := s.picks[expired]
+			if recent[old]--; recent[old] == 0 {
+				delete(recent, old)
+			}
+		}
+	}
+
+	return s.picks[day]
+}
+
+// DayNumber returns the number of days between the epoch and the calendar date of t
+func DayNumber(t time.Time) int {
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(date.Sub(Epoch).Hours() / 24)
+}
+
+// draw deterministically draws an index for a day, skipping indices in exclude
+func draw(fingerprint uint64, day, size int, exclude map[int]int) int {
+	state := fingerprint ^ uint64(int64(day))*0x9E3779B97F4A7C15
+	for {
+		state = splitmix64(state)
+		index := int(state % uint64(size))
+		if exclude[index] == 0 {
+			return index
+		}
+	}
+}
+
+// splitmix64 is a small, well-distributed pseudo-random step function
+func splitmix64(x uint64) uint64 {
+	x += 0x9E3779B97F4A7C15
+	x = (x ^ (x >> 30)) * 0xBF58476D1CE4E5B9
+	x = (x ^ (x >> 27)) * 0x94D049BB133111EB
+	return x ^ (x >> 31)
+}
@@ -0,0 +1,92 @@
+package daily
+
+import (
+	"testing"
+	"time"
+)
+
+// The fixed corpus the tests pick from
+const (
+	corpusSize        = 500
+	corpusFingerprint = 0x5EED
+)
+
+func TestPickAcrossTimeZones(t *testing.T) {
+	zone := func(name string) *time.Location {
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return location
+	}
+
+	// Instants in different zones that all fall on 10 March 2025 locally
+	dates := []time.Time{
+		time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.March, 10, 23, 59, 0, 0, zone("America/Los_Angeles")),
+		time.Date(2025, time.March, 10, 0, 30, 0, 0, zone("Asia/Jakarta")),
+		time.Date(2025, time.March, 10, 12, 0, 0, 0, zone("Pacific/Kiritimati")),
+	}
+
+	want := NewSelector(365).Pick(dates[0], corpusSize, corpusFingerprint)
+	for _, date := range dates[1:] {
+		// A fresh selector, and one that has already gone past the date
+		later := NewSelector(365)
+		later.Pick(date.AddDate(0, 2, 0), corpusSize, corpusFingerprint)
+
+		for _, s := range []*Selector{NewSelector(365), later} {
+			if got := s.Pick(date, corpusSize, corpusFingerprint); got != want {
+				t.Errorf("Pick(%s) = %d, want %d", date.Format(time.RFC3339), got, want)
+			}
+		}
+	}
+}
+
+func TestPickRepeatWindow(t *testing.T) {
+	tests := []struct {
+		window int
+		size   int
+		days   int
+	}{
+		{365, corpusSize, 3 * 365},
+		{30, corpusSize, 365},
+		// A window as large as the corpus cycles through all of it
+		{365, 7, 100},
+		{0, 7, 100},
+	}
+
+	for _, tt := range tests {
+		s := NewSelector(tt.window)
+		window := tt.window
+		if window > tt.size-1 {
+			window = tt.size - 1
+		}
+
+		var picks []int
+		for d := 0; d < tt.days; d++ {
+			pick := s.Pick(Epoch.AddDate(0, 0, d), tt.size, corpusFingerprint)
+			if pick < 0 || pick >= tt.size {
+				t.Fatalf("window %d, size %d: pick %d on day %d is out of range", tt.window, tt.size, pick, d)
+			}
+			for back := 1; back <= window && back <= d; back++ {
+				if picks[d-back] == pick {
+					t.Fatalf("window %d, size %d: pick %d on day %d repeats day %d", tt.window, tt.size, pick, d, d-back)
+				}
+			}
+			picks = append(picks, pick)
+		}
+	}
+}
+
+func TestPickCorpusChange(t *testing.T) {
+	date := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+
+	s := NewSelector(365)
+	want := s.Pick(date, corpusSize, corpusFingerprint)
+	s.Pick(date, corpusSize, corpusFingerprint+1)
+
+	// Switching back to the corpus gives the same pick again
+	if got := s.Pick(date, corpusSize, corpusFingerprint); got != want {
+		t.Errorf("Pick after a corpus change = %d, want %d", got, want)
+	}
+}
@@ -28,41 +28,129 @@ var docTemplate = `{
     "host": "%s",
     "basePath": "/api/v1",
     "paths": {
-        "/hadis": {
+        "/bundle": {
             "get": {
-                "description": "Returns all hadiths with pagination and optional search filtering",
+                "description": "Returns every collection compiled into a single SQLite file with collection metadata, a schema_version table and an FTS5 index on the normalized Arabic text and translation. The ETag is the dataset version, so clients can check for updates with If-None-Match.",
                 "produces": [
-                    "application/json"
+                    "application/vnd.sqlite3"
                 ],
                 "tags": [
-                    "hadiths"
+                    "export"
                 ],
-                "summary": "Get all hadiths",
+                "summary": "Download the offline bundle",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Bundle unchanged"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/changes": {
+            "get": {
+                "description": "Returns the hadiths added, modified and removed since a dataset revision, so offline clients can catch up without downloading everything again. Added and modified hadiths carry their full content and hash. Revision 0 is the empty dataset.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "sync"
+                ],
+                "summary": "Get changes since a revision",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number for pagination (default: 1)",
-                        "name": "page",
+                        "description": "Revision the client has (default: 0)",
+                        "name": "since",
                         "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.HadithResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ChangeSet"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
-                    {
-                        "type": "integer",
-                        "description": "Items per page for pagination (default: 10)",
-                        "name": "limit",
-                        "in": "query"
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Search query to filter hadiths by ID (translation)",
-                        "name": "q",
-                        "in": "query"
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
                     }
+                }
+            }
+        },
+        "/clusters": {
+            "get": {
+                "description": "Returns the clusters found by the offline clustering job with their size and top keywords",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
                 ],
+                "tags": [
+                    "clusters"
+                ],
+                "summary": "Get thematic clusters",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PaginatedResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.HadithResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Cluster"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "500": {
@@ -74,40 +162,116 @@ var docTemplate = `{
                 }
             }
         },
-        "/hadis/{slug}": {
+        "/clusters/{id}/hadis": {
             "get": {
-                "description": "Returns all hadiths from a specific narrator with optional pagination and filtering",
+                "description": "Returns the hadiths assigned to a cluster across all narrators, with pagination",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "hadiths"
+                    "clusters"
                 ],
-                "summary": "Get hadiths by narrator",
+                "summary": "Get hadiths by cluster",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Narrator slug (e.g., muslim, bukhari)",
-                        "name": "slug",
+                        "type": "integer",
+                        "description": "Cluster ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Page number for pagination",
+                        "description": "Page number for pagination (default: 1)",
                         "name": "page",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Items per page for pagination",
+                        "description": "Items per page for pagination (default: 10)",
                         "name": "limit",
                         "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.SelectedHadith"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/compare": {
+            "get": {
+                "description": "Returns a word-level diff of the Arabic text and of the translation of two hadiths, as JSON or another negotiated format, or as an HTML page",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack",
+                    "text/html"
+                ],
+                "tags": [
+                    "analysis"
+                ],
+                "summary": "Compare two hadiths word by word",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First hadith as slug:number (e.g., malik:12)",
+                        "name": "a",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Second hadith as slug:number (e.g., darimi:34)",
+                        "name": "b",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "normalize (default) to ignore Arabic diacritics, or preserve to compare them",
+                        "name": "diacritics",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Search query to filter hadiths",
-                        "name": "q",
+                        "description": "json, xml, msgpack, text or html (default: negotiated from Accept)",
+                        "name": "format",
                         "in": "query"
                     }
                 ],
@@ -115,17 +279,29 @@ var docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.PaginatedResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.HadithResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Comparison"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -133,37 +309,90 @@ var docTemplate = `{
                 }
             }
         },
-        "/hadis/{slug}/{number}": {
+        "/concordance": {
             "get": {
-                "description": "Returns a specific hadith from a narrator by its number",
+                "description": "Returns every occurrence of a term across the loaded collections with its left and right context. Words are compared after normalization, so a term without diacritics matches the vocalized Arabic text.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "hadiths"
+                    "analysis"
                 ],
-                "summary": "Get hadith by narrator and number",
+                "summary": "Keyword-in-context concordance",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Narrator slug (e.g., muslim, bukhari)",
-                        "name": "slug",
-                        "in": "path",
+                        "description": "Word or phrase to find; a trailing * matches word prefixes",
+                        "name": "term",
+                        "in": "query",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Hadith number",
-                        "name": "number",
-                        "in": "path",
-                        "required": true
+                        "description": "Number of context words on either side (default: 5, max: 20)",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort by left or right context (default: corpus order)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only search the arab or id text",
+                        "name": "field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only search this narrator's collection",
+                        "name": "narrator",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for pagination (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page for pagination (default: 10)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.HadithResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.KWICLine"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
@@ -181,21 +410,36 @@ var docTemplate = `{
                 }
             }
         },
-        "/narrators": {
+        "/export": {
             "get": {
-                "description": "Returns a list of all available hadith narrators",
+                "description": "Streams every hadith of every narrator, each with its narrator slug, as NDJSON, CSV (UTF-8 with BOM) or a JSON array",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/plain"
                 ],
                 "tags": [
-                    "narrators"
+                    "export"
+                ],
+                "summary": "Export all collections",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ndjson (default), csv or json",
+                        "name": "format",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get list of available narrators",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.HadithResponse"
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
@@ -206,49 +450,1832 @@ var docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string"
-                },
-                "message": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                }
-            }
         },
-        "models.HadithResponse": {
-            "type": "object",
-            "properties": {
-                "data": {},
-                "message": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
+        "/export/{slug}": {
+            "get": {
+                "description": "Streams every hadith of a narrator's collection as NDJSON, CSV (UTF-8 with BOM) or a JSON array",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Narrator slug (e.g., muslim, bukhari)",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ndjson (default), csv or json",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "models.Pagination": {
-            "type": "object",
-            "properties": {
-                "current_page": {
-                    "type": "integer"
-                },
-                "per_page": {
-                    "type": "integer"
-                },
-                "total_items": {
-                    "type": "integer"
-                },
-                "total_pages": {
-                    "type": "integer"
+        "/export/{slug}.apkg": {
+            "get": {
+                "description": "Generates an Anki deck (.apkg) for memorization with the Arabic text on the front of each card and the translation and reference on the back. The deck holds the hadiths listed by /hadis/{slug} with the same search, grade and topic filters, optionally narrowed to a range of numbers. With cloze set, the matn is split into cloze deletions with a card for each.",
+                "produces": [
+                    "application/apkg"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export an Anki flashcard deck",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Narrator slug (e.g., muslim, bukhari)",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Range of hadith numbers (e.g., 1-40)",
+                        "name": "range",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Topic slug (e.g., prayer)",
+                        "name": "topic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query to filter hadiths",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by grade (sahih, hasan, daif, maudu or ungraded)",
+                        "name": "grade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Words per cloze deletion on the matn (default: 0, no cloze)",
+                        "name": "cloze",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/export/{slug}.epub": {
+            "get": {
+                "description": "Generates a PDF or EPUB booklet with right-to-left Arabic, the Indonesian translation, a table of contents and a citation for every hadith. The booklet holds a list of references, a range of numbers or the hadiths of a topic, at most 100 hadiths.",
+                "produces": [
+                    "application/pdf",
+                    "application/epub+zip"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export a printable booklet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Narrator slug (e.g., muslim, bukhari)",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated hadith numbers, or slug:number for other collections (e.g., 1,5,bukhari:8)",
+                        "name": "refs",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Range of hadith numbers (e.g., 1-40)",
+                        "name": "range",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Topic slug (e.g., prayer)",
+                        "name": "topic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/export/{slug}.pdf": {
+            "get": {
+                "description": "Generates a PDF or EPUB booklet with right-to-left Arabic, the Indonesian translation, a table of contents and a citation for every hadith. The booklet holds a list of references, a range of numbers or the hadiths of a topic, at most 100 hadiths.",
+                "produces": [
+                    "application/pdf",
+                    "application/epub+zip"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export a printable booklet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Narrator slug (e.g., muslim, bukhari)",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated hadith numbers, or slug:number for other collections (e.g., 1,5,bukhari:8)",
+                        "name": "refs",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Range of hadith numbers (e.g., 1-40)",
+                        "name": "range",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Topic slug (e.g., prayer)",
+                        "name": "topic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/export/{slug}/tei": {
+            "get": {
+                "description": "Streams a narrator's collection, or a range of it, as a TEI XML document with the Arabic text, translation, numbering and book and chapter structure where present",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export a collection as TEI XML",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Narrator slug (e.g., muslim, bukhari)",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Range of hadith numbers to export (e.g., 1-40)",
+                        "name": "range",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/hadis": {
+            "get": {
+                "description": "Returns all hadiths with pagination and optional search filtering",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "hadiths"
+                ],
+                "summary": "Get all hadiths",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number for pagination (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page for pagination (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query to filter hadiths by ID (translation)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by grade (sahih, hasan, daif, maudu, ungraded)",
+                        "name": "grade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by topic slug (e.g., prayer, fasting)",
+                        "name": "topic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaginatedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/hadis/batch": {
+            "post": {
+                "description": "Looks up a list of hadith references across narrators. Results are returned in request order, with an error for each reference that could not be found.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "hadiths"
+                ],
+                "summary": "Get several hadiths at once",
+                "parameters": [
+                    {
+                        "description": "References to look up",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.HadithResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.BatchResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/hadis/daily": {
+            "get": {
+                "description": "Returns the hadith of the day with its Hijri date. The selection depends only on the date and the corpus, so every client gets the same hadith on the same date, and hadiths are not repeated within the configured window. During occasions such as Ramadan, Dhul Hijjah and Jumu'ah the hadith is taken from the occasion's configured list.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "hadiths"
+                ],
+                "summary": "Get the hadith of the day",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Date in YYYY-MM-DD format (default: today in the given time zone)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone used to determine today's date (default: UTC)",
+                        "name": "tz",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Set to false to ignore occasion-specific selections (default: true)",
+                        "name": "occasions",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.HadithResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.DailyHadith"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/hadis/random": {
+            "get": {
+                "description": "Returns a random hadith, optionally restricted by narrator, grade and translation length",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "hadiths"
+                ],
+                "summary": "Get a random hadith",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Narrator slug to pick from",
+                        "name": "narrator",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by grade (sahih, hasan, daif, maudu, ungraded)",
+                        "name": "grade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum length of the translation in characters",
+                        "name": "min_length",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum length of the translation in characters",
+                        "name": "max_length",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.HadithResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.SelectedHadith"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/hadis/{slug}": {
+            "get": {
+                "description": "Returns all hadiths from a specific narrator with optional pagination and filtering",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "hadiths"
+                ],
+                "summary": "Get hadiths by narrator",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Narrator slug (e.g., muslim, bukhari)",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page for pagination",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search query to filter hadiths",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by grade (sahih, hasan, daif, maudu, ungraded)",
+                        "name": "grade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by topic slug (e.g., prayer, fasting)",
+                        "name": "topic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaginatedResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/hadis/{slug}/{number}": {
+            "get": {
+                "description": "Returns a specific hadith from a narrator by its number, optionally in an alternate numbering scheme. A range such as 1-40 returns up to 100 hadiths.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "hadiths"
+                ],
+                "summary": "Get hadith by narrator and number",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Narrator slug (e.g., muslim, bukhari)",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hadith number, optionally with a sub-letter (e.g., 12 or 12a), or a range (e.g., 1-40)",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Numbering scheme of the given number (default: default)",
+                        "name": "scheme",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to citation to include a formatted citation",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Citation style for include=citation: hr, academic, bibtex or csl-json (default: hr)",
+                        "name": "style",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HadithResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/hadis/{slug}/{number}/cite": {
+            "get": {
+                "description": "Formats a citation of a hadith using the collection's name, title and compiler",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "hadiths"
+                ],
+                "summary": "Get a formatted citation of a hadith",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Narrator slug (e.g., muslim, bukhari)",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hadith number, optionally with a sub-letter (e.g., 12 or 12a)",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Citation style: hr, academic, bibtex or csl-json (default: hr)",
+                        "name": "style",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Numbering scheme of the given number (default: default)",
+                        "name": "scheme",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.HadithResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Citation"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/hadis/{slug}/{number}/concordance": {
+            "get": {
+                "description": "Translates a hadith number from one numbering scheme into the other schemes known for the collection",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "hadiths"
+                ],
+                "summary": "Get hadith numbers across numbering schemes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Narrator slug (e.g., muslim, bukhari)",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hadith number, optionally with a sub-letter (e.g., 12 or 12a)",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Numbering scheme of the given number (default: default)",
+                        "name": "scheme",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only translate into this scheme",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.HadithResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Concordance"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/narrators": {
+            "get": {
+                "description": "Returns a list of all available hadith narrators",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "narrators"
+                ],
+                "summary": "Get list of available narrators",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HadithResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/narrators/{slug}": {
+            "get": {
+                "description": "Returns metadata for a narrator's collection, including a summary of hadith grades",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "narrators"
+                ],
+                "summary": "Get collection metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Narrator slug (e.g., muslim, bukhari)",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.HadithResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Collection"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ngrams": {
+            "get": {
+                "description": "Returns the most frequent n-grams of 2 to 5 normalized words and the most significant two-word collocations, for a collection or the whole corpus",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "analysis"
+                ],
+                "summary": "N-gram and collocation analysis",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only analyze this narrator's collection (default: the whole corpus)",
+                        "name": "narrator",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text to analyze: arab or id (default: arab)",
+                        "name": "field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Shortest n-gram length (default: 2)",
+                        "name": "min_n",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Longest n-gram length (default: 5)",
+                        "name": "max_n",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ignore n-grams occurring fewer times (default: 5)",
+                        "name": "min_count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of n-grams and collocations to return (default: 50, max: 1000)",
+                        "name": "top",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Collocation measure: pmi or llr (default: pmi)",
+                        "name": "measure",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.HadithResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.NGramReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/resolve": {
+            "get": {
+                "description": "Parses a citation such as \"HR. Malik no. 12\", \"Muwatta 1/23\" or \"رواه الدارمي ٢٩٤٩\" and returns the matching hadith or a ranked list of candidates",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "hadiths"
+                ],
+                "summary": "Resolve a free-text citation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Citation text in Indonesian, English or Arabic",
+                        "name": "ref",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.HadithResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ResolvedCitation"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "description": "Returns hadith counts, number ranges and gaps, text lengths, vocabulary size and top words for the whole corpus and each collection",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get corpus statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.HadithResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Stats"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/{slug}": {
+            "get": {
+                "description": "Returns the hadith count, number range and gaps, text lengths, vocabulary size and top words of a narrator's collection",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get collection statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Narrator slug (e.g., muslim, bukhari)",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.HadithResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CollectionStats"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/topics": {
+            "get": {
+                "description": "Returns all topics with the number of hadiths tagged with each",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "topics"
+                ],
+                "summary": "Get the topic taxonomy",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.HadithResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Topic"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/topics/{topic}/hadis": {
+            "get": {
+                "description": "Returns the hadiths tagged with a topic across all narrators, with pagination",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "text/plain",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "topics"
+                ],
+                "summary": "Get hadiths by topic",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Topic slug (e.g., prayer, fasting)",
+                        "name": "topic",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return hadiths from this narrator",
+                        "name": "narrator",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number for pagination (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page for pagination (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.SelectedHadith"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.BatchReference": {
+            "type": "object",
+            "properties": {
+                "number": {
+                    "description": "Number is an integer, or a string such as \"12a\" for a number with a letter suffix",
+                    "type": "integer"
+                },
+                "scheme": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BatchRequest": {
+            "type": "object",
+            "properties": {
+                "references": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchReference"
+                    }
+                }
+            }
+        },
+        "models.BatchResponse": {
+            "type": "object",
+            "properties": {
+                "found": {
+                    "type": "integer"
+                },
+                "not_found": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchResult"
+                    }
+                }
+            }
+        },
+        "models.BatchResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "hadith": {
+                    "$ref": "#/definitions/models.Hadith"
+                },
+                "number": {
+                    "description": "Number is an integer, or a string such as \"12a\" when it has a letter suffix",
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChangeSet": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SelectedHadith"
+                    }
+                },
+                "modified": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SelectedHadith"
+                    }
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HadithReference"
+                    }
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "since": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Citation": {
+            "type": "object",
+            "properties": {
+                "citation": {},
+                "style": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CitationCandidate": {
+            "type": "object",
+            "properties": {
+                "hadith": {
+                    "$ref": "#/definitions/models.Hadith"
+                },
+                "number": {
+                    "description": "Number is an integer, or a string such as \"12a\" when it has a letter suffix",
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Cluster": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "keywords": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "total_hadiths": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Collection": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "compiler": {
+                    "type": "string"
+                },
+                "grades": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "schemes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "total_hadiths": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CollectionStats": {
+            "type": "object",
+            "properties": {
+                "arab": {
+                    "$ref": "#/definitions/models.TextStats"
+                },
+                "first_number": {
+                    "description": "FirstNumber is an integer, or a string such as \"12a\" when it has a letter suffix",
+                    "type": "integer"
+                },
+                "gaps": {
+                    "description": "Gaps are the ranges of numbers between the first and last that no hadith has",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NumberRange"
+                    }
+                },
+                "id": {
+                    "$ref": "#/definitions/models.TextStats"
+                },
+                "last_number": {
+                    "description": "LastNumber is an integer, or a string such as \"12a\" when it has a letter suffix",
+                    "type": "integer"
+                },
+                "missing_numbers": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "total_hadiths": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Collocation": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "log_likelihood": {
+                    "type": "number"
+                },
+                "pmi": {
+                    "type": "number"
+                },
+                "words": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Comparison": {
+            "type": "object",
+            "properties": {
+                "a": {
+                    "$ref": "#/definitions/models.SelectedHadith"
+                },
+                "arab": {
+                    "$ref": "#/definitions/models.TextDiff"
+                },
+                "b": {
+                    "$ref": "#/definitions/models.SelectedHadith"
+                },
+                "diacritics": {
+                    "type": "string"
+                },
+                "id": {
+                    "$ref": "#/definitions/models.TextDiff"
+                }
+            }
+        },
+        "models.Concordance": {
+            "type": "object",
+            "properties": {
+                "number": {
+                    "$ref": "#/definitions/models.Number"
+                },
+                "references": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/models.Number"
+                        }
+                    }
+                },
+                "scheme": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DailyHadith": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "hadith": {
+                    "$ref": "#/definitions/models.Hadith"
+                },
+                "hijri": {
+                    "$ref": "#/definitions/models.HijriDate"
+                },
+                "occasion": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DiffOp": {
+            "type": "object",
+            "properties": {
+                "a": {
+                    "type": "string"
+                },
+                "b": {
+                    "type": "string"
+                },
+                "op": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Grade": {
+            "type": "object",
+            "properties": {
+                "grade": {
+                    "type": "string"
+                },
+                "grader": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Hadith": {
+            "type": "object",
+            "properties": {
+                "arab": {
+                    "type": "string"
+                },
+                "book": {
+                    "description": "Book and Chapter are the headings the hadith is found under, where the collection has them",
+                    "type": "string"
+                },
+                "chapter": {
+                    "type": "string"
+                },
+                "grades": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Grade"
+                    }
+                },
+                "hash": {
+                    "description": "Hash is the content hash, set as the hadith is loaded, see ContentHash",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "number": {
+                    "description": "Number is an integer, or a string such as \"12a\" when it has a letter suffix",
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Tag"
+                    }
+                }
+            }
+        },
+        "models.HadithReference": {
+            "type": "object",
+            "properties": {
+                "number": {
+                    "description": "Number is an integer, or a string such as \"12a\" when it has a letter suffix",
+                    "type": "integer"
+                },
+                "scheme": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HadithResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HijriDate": {
+            "type": "object",
+            "properties": {
+                "day": {
+                    "type": "integer"
+                },
+                "formatted": {
+                    "type": "string"
+                },
+                "month": {
+                    "type": "integer"
+                },
+                "month_name": {
+                    "type": "string"
+                },
+                "month_name_arabic": {
+                    "type": "string"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.KWICLine": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field is the text the term was found in: arab or id",
+                    "type": "string"
+                },
+                "keyword": {
+                    "type": "string"
+                },
+                "left": {
+                    "type": "string"
+                },
+                "number": {
+                    "description": "Number is an integer, or a string such as \"12a\" when it has a letter suffix",
+                    "type": "integer"
+                },
+                "right": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LongestHadith": {
+            "type": "object",
+            "properties": {
+                "length": {
+                    "type": "integer"
+                },
+                "number": {
+                    "description": "Number is an integer, or a string such as \"12a\" when it has a letter suffix",
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NGram": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "n": {
+                    "type": "integer"
+                },
+                "words": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.NGramReport": {
+            "type": "object",
+            "properties": {
+                "collocations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Collocation"
+                    }
+                },
+                "field": {
+                    "type": "string"
+                },
+                "max_n": {
+                    "type": "integer"
+                },
+                "measure": {
+                    "type": "string"
+                },
+                "min_count": {
+                    "type": "integer"
+                },
+                "min_n": {
+                    "type": "integer"
+                },
+                "narrator": {
+                    "description": "Narrator is empty for the whole corpus",
+                    "type": "string"
+                },
+                "ngrams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NGram"
+                    }
+                }
+            }
+        },
+        "models.Number": {
+            "type": "object",
+            "properties": {
+                "suffix": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.NumberRange": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
                 }
             }
         },
@@ -266,6 +2293,161 @@ var docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.Pagination": {
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "type": "integer"
+                },
+                "per_page": {
+                    "type": "integer"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ResolvedCitation": {
+            "type": "object",
+            "properties": {
+                "book": {
+                    "type": "integer"
+                },
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CitationCandidate"
+                    }
+                },
+                "collection": {
+                    "type": "string"
+                },
+                "match": {
+                    "$ref": "#/definitions/models.CitationCandidate"
+                },
+                "number": {
+                    "description": "Number is an integer, or a string such as \"12a\" when it has a letter suffix",
+                    "type": "integer"
+                },
+                "query": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SelectedHadith": {
+            "type": "object",
+            "properties": {
+                "hadith": {
+                    "$ref": "#/definitions/models.Hadith"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Stats": {
+            "type": "object",
+            "properties": {
+                "arab": {
+                    "$ref": "#/definitions/models.TextStats"
+                },
+                "collections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CollectionStats"
+                    }
+                },
+                "id": {
+                    "$ref": "#/definitions/models.TextStats"
+                },
+                "total_hadiths": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Tag": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "type": "number"
+                },
+                "topic": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TextDiff": {
+            "type": "object",
+            "properties": {
+                "ops": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DiffOp"
+                    }
+                },
+                "similarity": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.TextStats": {
+            "type": "object",
+            "properties": {
+                "average_length": {
+                    "type": "number"
+                },
+                "average_words": {
+                    "type": "number"
+                },
+                "longest": {
+                    "$ref": "#/definitions/models.LongestHadith"
+                },
+                "top_words": {
+                    "description": "TopWords are the most frequent normalized words, excluding stopwords",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WordCount"
+                    }
+                },
+                "vocabulary_size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Topic": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "name_ar": {
+                    "type": "string"
+                },
+                "name_en": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "total_hadiths": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.WordCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "word": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
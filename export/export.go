@@ -0,0 +1,166 @@
+// Package export writes hadiths in bulk download formats. Writers emit each
+// hadith as it is written, so exports can be streamed to the client.
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hadith-api/models"
+)
+
+// Writer writes hadiths one at a time in an export format
+type Writer interface {
+	// WriteHadith writes a hadith from the given narrator's collection
+	WriteHadith(slug string, h models.Hadith) error
+	// Flush writes out the hadiths buffered so far
+	Flush() error
+	// Close finishes the export and flushes any buffered output
+	Close() error
+}
+
+// Format describes an export format
+type Format struct {
+	ContentType string
+	Extension   string
+	// New creates a writer; corpus exports include the narrator slug with every hadith
+	New func(w io.Writer, corpus bool) Writer
+}
+
+// Formats lists the available export formats by name
+var Formats = map[string]Format{
+	"ndjson": {ContentType: "application/x-ndjson", Extension: "ndjson", New: newNDJSONWriter},
+	"json":   {ContentType: "application/json; charset=utf-8", Extension: "json", New: newJSONWriter},
+	"csv":    {ContentType: "text/csv; charset=utf-8", Extension: "csv", New: newCSVWriter},
+}
+
+// record returns what is written for a hadith: the hadith itself in the
+// format the data files use, or together with its narrator for corpus exports
+func record(slug string, h models.Hadith, corpus bool) interface{} {
+	if corpus {
+		return models.SelectedHadith{Slug: slug, Hadith: &h}
+	}
+	return h
+}
+
+// ndjsonWriter writes one JSON object per line
+type ndjsonWriter struct {
+	w      *bufio.Writer
+	corpus bool
+}
+
+func newNDJSONWriter(w io.Writer, corpus bool) Writer {
+	return &ndjsonWriter{w: bufio.NewWriter(w), corpus: corpus}
+}
+
+func (n *ndjsonWriter) WriteHadith(slug string, h models.Hadith) error {
+	encoded, err := json.Marshal(record(slug, h, n.corpus))
+	if err != nil {
+		return fmt.Errorf("failed to encode hadith %s %s: %w", slug, h.Number, err)
+	}
+	n.w.Write(encoded)
+	return n.w.WriteByte('\n')
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return n.w.Flush()
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.w.Flush()
+}
+
+// jsonWriter writes a single JSON array
+type jsonWriter struct {
+	w       *bufio.Writer
+	corpus  bool
+	written int
+}
+
+func newJSONWriter(w io.Writer, corpus bool) Writer {
+	return &jsonWriter{w: bufio.NewWriter(w), corpus: corpus}
+}
+
+func (j *jsonWriter) WriteHadith(slug string, h models.Hadith) error {
+	encoded, err := json.Marshal(record(slug, h, j.corpus))
+	if err != nil {
+		return fmt.Errorf("failed to encode hadith %s %s: %w", slug, h.Number, err)
+	}
+	if j.written == 0 {
+		j.w.WriteString("[\n")
+	} else {
+		j.w.WriteString(",\n")
+	}
+	j.written++
+	_, err = j.w.Write(encoded)
+	return err
+}
+
+func (j *jsonWriter) Flush() error {
+	return j.w.Flush()
+}
+
+func (j *jsonWriter) Close() error {
+	if j.written == 0 {
+		j.w.WriteString("[")
+	}
+	j.w.WriteString("\n]\n")
+	return j.w.Flush()
+}
+
+// csvHeader lists the columns of a CSV export
+var csvHeader = []string{"slug", "number", "arab", "id", "grades", "tags"}
+
+// csvWriter writes UTF-8 CSV with a byte order mark, so spreadsheet tools
+// detect the encoding of the Arabic text
+type csvWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func newCSVWriter(w io.Writer, corpus bool) Writer {
+	io.WriteString(w, "\xEF\xBB\xBF")
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteHadith(slug string, h models.Hadith) error {
+	if !c.header {
+		c.header = true
+		if err := c.w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+
+	grades := make([]string, len(h.Grades))
+	for i, g := range h.Grades {
+		grades[i] = g.Grade
+		if g.Grader != "" {
+			grades[i] = g.Grader + ": " + g.Grade
+		}
+	}
+
+	tags := make([]string, len(h.Tags))
+	for i, t := range h.Tags {
+		tags[i] = t.Topic
+	}
+
+	c.w.Write([]string{slug, h.Number.String(), h.Arab, h.ID, strings.Join(grades, "; "), strings.Join(tags, "; ")})
+	return c.w.Error()
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	if !c.header {
+		c.w.Write(csvHeader)
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
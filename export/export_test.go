@@ -0,0 +1,134 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hadith-api/models"
+)
+
+var testHadiths = []models.Hadith{
+	{
+		Number: models.Number{Value: 1},
+		Arab:   "إِنَّمَا الْأَعْمَالُ بِالنِّيَّاتِ",
+		ID:     `Amal itu, "kata beliau", tergantung niatnya.`,
+		Grades: []models.Grade{{Grader: "Al-Albani", Grade: "Shahih"}, {Grade: "Hasan"}},
+		Tags:   []models.Tag{{Topic: "intention"}, {Topic: "faith"}},
+	},
+	{Number: models.Number{Value: 2, Suffix: "a"}, Arab: "الدِّينُ النَّصِيحَةُ", ID: "Agama itu nasihat."},
+}
+
+// export writes the test hadiths in a format and returns the output
+func export(t *testing.T, name string, corpus bool, hadiths []models.Hadith) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := Formats[name].New(&buf, corpus)
+	for _, h := range hadiths {
+		if err := w.WriteHadith("malik", h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestNDJSON(t *testing.T) {
+	lines := strings.Split(export(t, "ndjson", false, testHadiths), "\n")
+	if len(lines) != 3 || lines[2] != "" {
+		t.Fatalf("lines = %q, want one per hadith", lines)
+	}
+	for i, line := range lines[:2] {
+		var got models.Hadith
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, testHadiths[i]) {
+			t.Errorf("line %d = %+v, want %+v", i, got, testHadiths[i])
+		}
+	}
+
+	var got models.SelectedHadith
+	line, _, _ := strings.Cut(export(t, "ndjson", true, testHadiths), "\n")
+	if err := json.Unmarshal([]byte(line), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Slug != "malik" || got.Hadith == nil || got.Hadith.Number != testHadiths[0].Number {
+		t.Errorf("corpus line = %s, want the hadith with its slug", line)
+	}
+
+	if got := export(t, "ndjson", false, nil); got != "" {
+		t.Errorf("empty export = %q, want nothing", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var got []models.Hadith
+	if err := json.Unmarshal([]byte(export(t, "json", false, testHadiths)), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, testHadiths) {
+		t.Errorf("export = %+v, want %+v", got, testHadiths)
+	}
+
+	var corpus []models.SelectedHadith
+	if err := json.Unmarshal([]byte(export(t, "json", true, testHadiths)), &corpus); err != nil {
+		t.Fatal(err)
+	}
+	if len(corpus) != 2 || corpus[1].Slug != "malik" || corpus[1].Hadith.Number != testHadiths[1].Number {
+		t.Errorf("corpus export = %+v, want the hadiths with their slug", corpus)
+	}
+
+	empty := export(t, "json", false, nil)
+	if err := json.Unmarshal([]byte(empty), &got); err != nil || len(got) != 0 {
+		t.Errorf("empty export = %q, want an empty array", empty)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	out := export(t, "csv", false, testHadiths)
+	if !strings.HasPrefix(out, "\xEF\xBB\xBF") {
+		t.Fatalf("export = %q, want a byte order mark", out)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(out, "\xEF\xBB\xBF"))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		csvHeader,
+		{"malik", "1", testHadiths[0].Arab, testHadiths[0].ID, "Al-Albani: Shahih; Hasan", "intention; faith"},
+		{"malik", "2a", testHadiths[1].Arab, testHadiths[1].ID, "", ""},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("records = %q, want %q", records, want)
+	}
+
+	// An empty export still has the byte order mark and the header
+	if got, want := export(t, "csv", false, nil), "\xEF\xBB\xBFslug,number,arab,id,grades,tags\n"; got != want {
+		t.Errorf("empty export = %q, want %q", got, want)
+	}
+}
+
+func TestFlush(t *testing.T) {
+	for name, format := range Formats {
+		var buf bytes.Buffer
+		w := format.New(&buf, false)
+		if err := w.WriteHadith("malik", testHadiths[1]); err != nil {
+			t.Fatal(err)
+		}
+		before := buf.Len()
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), "الدِّينُ النَّصِيحَةُ") || buf.Len() <= before {
+			t.Errorf("%s: Flush did not write out the buffered hadith: %q", name, buf.String())
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package format renders API responses as XML, MessagePack and plain text.
+// Every format is derived from the JSON representation of a response, so all
+// formats carry the same fields under the same names, in the same order.
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Member is a named value of an Object
+type Member struct {
+	Key   string
+	Value interface{}
+}
+
+// Object is a JSON object with its members in their original order
+type Object []Member
+
+// Get returns the value of a member and whether it is present
+func (o Object) Get(key string) (interface{}, bool) {
+	for _, m := range o {
+		if m.Key == key {
+			return m.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Decode converts v into its JSON representation: an Object, []interface{},
+// string, json.Number, bool or nil
+func Decode(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decodeValue(decoder)
+}
+
+func decodeValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := Object{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, Member{Key: key.(string), Value: value})
+		}
+		_, err := decoder.Token()
+		return object, err
+	case json.Delim('['):
+		array := []interface{}{}
+		for decoder.More() {
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err := decoder.Token()
+		return array, err
+	default:
+		return token, nil
+	}
+}
+
+// Plain converts a decoded value into maps, slices and Go numbers, as
+// expected by generic encoders such as MessagePack
+func Plain(v interface{}) interface{} {
+	switch v := v.(type) {
+	case Object:
+		m := make(map[string]interface{}, len(v))
+		for _, member := range v {
+			m[member.Key] = Plain(member.Value)
+		}
+		return m
+	case []interface{}:
+		array := make([]interface{}, len(v))
+		for i, value := range v {
+			array[i] = Plain(value)
+		}
+		return array
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	default:
+		return v
+	}
+}
+
+// scalar returns the text of a string, number, bool or null value
+func scalar(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return fmt.Sprint(v), true
+	case nil:
+		return "", true
+	default:
+		return "", false
+	}
+}
+
+// errWriter writes strings to w, keeping the first error
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) write(s string) {
+	if e.err == nil {
+		_, e.err = io.WriteString(e.w, s)
+	}
+}
@@ -0,0 +1,111 @@
+package format
+
+import (
+	"io"
+	"strings"
+)
+
+// WriteText writes a decoded value as readable plain text. Hadiths, objects
+// with both an "arab" and an "id" text, are shown with the Arabic above the
+// translation; other values are listed as "key: value" lines, indented by nesting.
+func WriteText(w io.Writer, v interface{}) error {
+	out := &errWriter{w: w}
+	writeText(out, v, "")
+	return out.err
+}
+
+func writeText(out *errWriter, v interface{}, indent string) {
+	switch v := v.(type) {
+	case Object:
+		if isHadith(v) {
+			writeHadith(out, v, indent)
+			return
+		}
+		for _, m := range v {
+			writeMember(out, m, indent)
+		}
+	case []interface{}:
+		for i, item := range v {
+			if i > 0 {
+				out.write("\n")
+			}
+			writeText(out, item, indent)
+		}
+	default:
+		text, _ := scalar(v)
+		out.write(indent + text + "\n")
+	}
+}
+
+// writeMember writes a member inline when it is a scalar or a list of
+// scalars, and as an indented block otherwise
+func writeMember(out *errWriter, m Member, indent string) {
+	if text, ok := inline(m.Value); ok {
+		out.write(indent + m.Key + ": " + text + "\n")
+		return
+	}
+	out.write(indent + m.Key + ":\n")
+	writeText(out, m.Value, indent+"  ")
+}
+
+// writeHadith writes the scalar members of a hadith such as its number as a
+// heading, then the Arabic text and the translation, then any other members
+func writeHadith(out *errWriter, hadith Object, indent string) {
+	var heading []string
+	var rest []Member
+	for _, m := range hadith {
+		switch m.Key {
+		case "arab", "id":
+			continue
+		}
+		if text, ok := scalar(m.Value); ok {
+			heading = append(heading, m.Key+": "+text)
+		} else {
+			rest = append(rest, m)
+		}
+	}
+
+	arab, _ := hadith.Get("arab")
+	id, _ := hadith.Get("id")
+	if len(heading) > 0 {
+		out.write(indent + "[" + strings.Join(heading, ", ") + "]\n")
+	}
+	out.write(indent + strings.TrimSpace(arab.(string)) + "\n\n")
+	out.write(indent + strings.TrimSpace(id.(string)) + "\n")
+	for _, m := range rest {
+		writeMember(out, m, indent)
+	}
+}
+
+// isHadith reports whether an object holds the Arabic text and translation of a hadith
+func isHadith(o Object) bool {
+	arab, hasArab := o.Get("arab")
+	id, hasID := o.Get("id")
+	if !hasArab || !hasID {
+		return false
+	}
+	_, arabText := arab.(string)
+	_, idText := id.(string)
+	return arabText && idText
+}
+
+// inline returns the single-line form of a scalar or a list of scalars
+func inline(v interface{}) (string, bool) {
+	if text, ok := scalar(v); ok {
+		return text, true
+	}
+
+	array, ok := v.([]interface{})
+	if !ok {
+		return "", false
+	}
+	items := make([]string, len(array))
+	for i, item := range array {
+		text, ok := scalar(item)
+		if !ok {
+			return "", false
+		}
+		items[i] = text
+	}
+	return strings.Join(items, ", "), true
+}
@@ -0,0 +1,67 @@
+package format
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// WriteXML writes a decoded value as an XML document with the given root
+// element. Object members become child elements and array elements become
+// <item> elements. Keys that are not valid element names, such as hadith
+// numbers, are written as <entry key="...">.
+func WriteXML(w io.Writer, root string, v interface{}) error {
+	out := &errWriter{w: w}
+	out.write(xml.Header)
+	writeElement(out, root, v)
+	out.write("\n")
+	return out.err
+}
+
+func writeElement(out *errWriter, name string, v interface{}) {
+	open, close := "<"+name+">", "</"+name+">"
+	if !validName(name) {
+		var key strings.Builder
+		xml.EscapeText(&key, []byte(name))
+		open, close = `<entry key="`+key.String()+`">`, "</entry>"
+	}
+
+	out.write(open)
+	switch v := v.(type) {
+	case Object:
+		for _, m := range v {
+			writeElement(out, m.Key, m.Value)
+		}
+	case []interface{}:
+		for _, item := range v {
+			writeElement(out, "item", item)
+		}
+	default:
+		text, _ := scalar(v)
+		var escaped strings.Builder
+		xml.EscapeText(&escaped, []byte(text))
+		out.write(escaped.String())
+	}
+	out.write(close)
+}
+
+// validName reports whether name can be used as an XML element name as is
+func validName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+	for i, r := range name {
+		if r > unicode.MaxASCII {
+			return false
+		}
+		if unicode.IsLetter(r) || r == '_' {
+			continue
+		}
+		if i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.') {
+			continue
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/repository"
+)
+
+// maxBatchSize caps both the size of a number range and the number of references in a batch
+const maxBatchSize = 100
+
+// getHadithRange responds with the hadiths in a "from-to" number range, e.g. 1-40.
+// Both ends are resolved through the given numbering scheme.
+func (h *HadithHandler) getHadithRange(c *gin.Context, narrator, numberRange, scheme string) {
+	from, to, err := parseRange(numberRange)
+	if err != nil {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid hadith range",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if from, err = h.repoFor(c).ResolveNumber(narrator, scheme, from); err == nil {
+		to, err = h.repoFor(c).ResolveNumber(narrator, scheme, to)
+	}
+	if err != nil {
+		respond(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "Hadith not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if to.Value-from.Value >= maxBatchSize {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid hadith range",
+			Error:   fmt.Sprintf("A range may span at most %d hadiths", maxBatchSize),
+		})
+		return
+	}
+
+	hadiths, err := h.repoFor(c).GetHadithRange(narrator, from, to)
+	if err != nil {
+		respond(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to get hadiths",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, models.HadithResponse{
+		Status:  "success",
+		Message: "Hadiths retrieved successfully",
+		Data:    hadiths,
+	})
+}
+
+// parseRange parses a "from-to" range of hadith numbers
+func parseRange(s string) (models.Number, models.Number, error) {
+	fromStr, toStr, _ := strings.Cut(s, "-")
+
+	from, err := models.ParseNumber(fromStr)
+	if err != nil {
+		return models.Number{}, models.Number{}, err
+	}
+	to, err := models.ParseNumber(toStr)
+	if err != nil {
+		return models.Number{}, models.Number{}, err
+	}
+	if to.Less(from) {
+		return models.Number{}, models.Number{}, fmt.Errorf("range %s ends before it starts", s)
+	}
+
+	return from, to, nil
+}
+
+// GetHadithBatch godoc
+// @Summary      Get several hadiths at once
+// @Description  Looks up a list of hadith references across narrators. Results are returned in request order, with an error for each reference that could not be found.
+// @Tags         hadiths
+// @Accept       json
+// @Produce      json,xml,plain,application/msgpack
+// @Param        request  body      models.BatchRequest  true  "References to look up"
+// @Success      200      {object}  models.HadithResponse{data=models.BatchResponse}
+// @Failure      400      {object}  models.ErrorResponse
+// @Router       /hadis/batch [post]
+func (h *HadithHandler) GetHadithBatch(c *gin.Context) {
+	var request models.BatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid batch request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if len(request.References) == 0 || len(request.References) > maxBatchSize {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid batch request",
+			Error:   fmt.Sprintf("A batch must contain between 1 and %d references", maxBatchSize),
+		})
+		return
+	}
+
+	response := models.BatchResponse{
+		Results: make([]models.BatchResult, 0, len(request.References)),
+	}
+
+	for _, item := range request.References {
+		result := models.BatchResult{Slug: item.Slug}
+
+		ref, err := item.Reference()
+		var hadith *models.Hadith
+		if err == nil {
+			if ref.Number.Value != 0 {
+				result.Number = &ref.Number
+			}
+			hadith, err = h.lookupReference(h.repoFor(c), ref)
+		}
+		if err != nil {
+			result.Error = err.Error()
+			response.NotFound++
+		} else {
+			result.Hadith = hadith
+			response.Found++
+		}
+
+		response.Results = append(response.Results, result)
+	}
+
+	respond(c, http.StatusOK, models.HadithResponse{
+		Status:  "success",
+		Message: "Batch retrieved successfully",
+		Data:    response,
+	})
+}
+
+// lookupReference finds the hadith a reference points to, accepting narrator aliases
+func (h *HadithHandler) lookupReference(repo repository.Repository, ref models.HadithReference) (*models.Hadith, error) {
+	if ref.Number.Value == 0 {
+		return nil, fmt.Errorf("missing hadith number")
+	}
+
+	narrator, err := repo.ResolveNarrator(ref.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.GetHadithByScheme(narrator, ref.Scheme, ref.Number)
+}
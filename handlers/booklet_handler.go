@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/booklet"
+	"github.com/hadith-api/citation"
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/repository"
+)
+
+// ExportBooklet godoc
+// @Summary      Export a printable booklet
+// @Description  Generates a PDF or EPUB booklet with right-to-left Arabic, the Indonesian translation, a table of contents and a citation for every hadith. The booklet holds a list of references, a range of numbers or the hadiths of a topic, at most 100 hadiths.
+// @Tags         export
+// @Produce      application/pdf,application/epub+zip
+// @Param        slug   path      string  true  "Narrator slug (e.g., muslim, bukhari)"
+// @Param        refs   query     string  false "Comma-separated hadith numbers, or slug:number for other collections (e.g., 1,5,bukhari:8)"
+// @Param        range  query     string  false "Range of hadith numbers (e.g., 1-40)"
+// @Param        topic  query     string  false "Topic slug (e.g., prayer)"
+// @Success      200    {file}    file
+// @Failure      400    {object}  models.ErrorResponse
+// @Failure      404    {object}  models.ErrorResponse
+// @Router       /export/{slug}.pdf [get]
+// @Router       /export/{slug}.epub [get]
+func (h *HadithHandler) ExportBooklet(c *gin.Context) {
+	name, format, ok := booklet.Split(c.Param("slug"))
+	if !ok {
+		respondNegotiated(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid format",
+			Error:   "booklets are available as .pdf or .epub",
+		})
+		return
+	}
+
+	narrator, err := h.repoFor(c).ResolveNarrator(name)
+	if err != nil {
+		respondNegotiated(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "Narrator not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	collection, err := h.repoFor(c).GetCollectionInfo(narrator)
+	if err != nil {
+		respondNegotiated(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "Collection not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	title := collection.Title
+	if title == "" {
+		title = collection.Name
+	}
+
+	var selected []models.SelectedHadith
+	var subtitle string
+	filename := narrator
+	switch {
+	case c.Query("refs") != "":
+		refs, err := parseBookletReferences(narrator, c.Query("refs"))
+		if err != nil {
+			respondNegotiated(c, http.StatusBadRequest, models.ErrorResponse{
+				Status:  "error",
+				Message: "Invalid references",
+				Error:   err.Error(),
+			})
+			return
+		}
+		selected, err = h.bookletReferences(h.repoFor(c), refs)
+		if err != nil {
+			respondNegotiated(c, http.StatusNotFound, models.ErrorResponse{
+				Status:  "error",
+				Message: "Hadith not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+		subtitle = "Pilihan hadits"
+		filename += "-selection"
+
+	case c.Query("range") != "":
+		from, to, err := parseRange(c.Query("range"))
+		if err != nil {
+			respondNegotiated(c, http.StatusBadRequest, models.ErrorResponse{
+				Status:  "error",
+				Message: "Invalid range",
+				Error:   err.Error(),
+			})
+			return
+		}
+		if to.Value-from.Value >= maxBatchSize {
+			respondNegotiated(c, http.StatusBadRequest, models.ErrorResponse{
+				Status:  "error",
+				Message: "Invalid range",
+				Error:   fmt.Sprintf("A booklet may span at most %d hadiths", maxBatchSize),
+			})
+			return
+		}
+		hadiths, err := h.repoFor(c).GetHadithRange(narrator, from, to)
+		if err != nil {
+			respondNegotiated(c, http.StatusNotFound, models.ErrorResponse{
+				Status:  "error",
+				Message: "Failed to get hadiths",
+				Error:   err.Error(),
+			})
+			return
+		}
+		selected = selectHadiths(narrator, hadiths)
+		subtitle = fmt.Sprintf("No. %s–%s", from, to)
+		filename = fmt.Sprintf("%s-%s-%s", narrator, from, to)
+
+	case c.Query("topic") != "":
+		topic, err := h.repoFor(c).GetTopic(c.Query("topic"))
+		if err != nil {
+			respondNegotiated(c, http.StatusNotFound, models.ErrorResponse{
+				Status:  "error",
+				Message: "Topic not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+		hadiths, _, err := h.repoFor(c).GetHadithsByNarrator(narrator, models.QueryParams{Topic: topic.Slug})
+		if err != nil {
+			respondNegotiated(c, http.StatusInternalServerError, models.ErrorResponse{
+				Status:  "error",
+				Message: "Failed to get hadiths",
+				Error:   err.Error(),
+			})
+			return
+		}
+		if len(hadiths) > maxBatchSize {
+			respondNegotiated(c, http.StatusBadRequest, models.ErrorResponse{
+				Status:  "error",
+				Message: "Topic too large",
+				Error:   fmt.Sprintf("%d hadiths of %s are tagged %s, a booklet may hold at most %d; list them with refs or range instead", len(hadiths), narrator, topic.Slug, maxBatchSize),
+			})
+			return
+		}
+		selected = selectHadiths(narrator, hadiths)
+		subtitle = topic.Name
+		filename = fmt.Sprintf("%s-%s", narrator, topic.Slug)
+
+	default:
+		// A whole collection would be laid out in memory at once
+		respondNegotiated(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Missing selection",
+			Error:   fmt.Sprintf("Select at most %d hadiths with refs, range or topic", maxBatchSize),
+		})
+		return
+	}
+
+	if len(selected) == 0 {
+		respondNegotiated(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "No hadiths found",
+			Error:   "the selection is empty",
+		})
+		return
+	}
+
+	entries, err := h.bookletEntries(h.repoFor(c), selected)
+	if err != nil {
+		respondNegotiated(c, http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to prepare booklet",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format.Extension))
+	c.Status(http.StatusOK)
+
+	if err := format.Write(c.Writer, booklet.New(title, subtitle, entries)); err != nil {
+		log.Printf("Booklet export of %s stopped: %v", filename, err)
+	}
+}
+
+// parseBookletReferences parses a comma-separated list of references. Bare
+// numbers refer to the narrator's collection, slug:number to any other.
+func parseBookletReferences(narrator, refs string) ([]models.HadithReference, error) {
+	parts := strings.Split(refs, ",")
+	if len(parts) > maxBatchSize {
+		return nil, fmt.Errorf("a booklet may list at most %d references", maxBatchSize)
+	}
+
+	parsed := make([]models.HadithReference, 0, len(parts))
+	for _, part := range parts {
+		slug, number := narrator, strings.TrimSpace(part)
+		if s, n, ok := strings.Cut(number, ":"); ok {
+			slug, number = strings.TrimSpace(s), strings.TrimSpace(n)
+		}
+
+		n, err := models.ParseNumber(number)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", strings.TrimSpace(part), err)
+		}
+		parsed = append(parsed, models.HadithReference{Slug: slug, Number: n})
+	}
+
+	return parsed, nil
+}
+
+// bookletReferences looks up the hadiths of a list of references
+func (h *HadithHandler) bookletReferences(repo repository.Repository, refs []models.HadithReference) ([]models.SelectedHadith, error) {
+	selected := make([]models.SelectedHadith, 0, len(refs))
+	for _, ref := range refs {
+		hadith, err := h.lookupReference(repo, ref)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%s: %w", ref.Slug, ref.Number, err)
+		}
+
+		resolved, _ := repo.ResolveNarrator(ref.Slug)
+		selected = append(selected, models.SelectedHadith{Slug: resolved, Hadith: hadith})
+	}
+
+	return selected, nil
+}
+
+// bookletEntries adds the collection name and "HR." citation to each hadith
+func (h *HadithHandler) bookletEntries(repo repository.Repository, selected []models.SelectedHadith) ([]booklet.Entry, error) {
+	collections := make(map[string]*models.Collection)
+
+	entries := make([]booklet.Entry, 0, len(selected))
+	for _, s := range selected {
+		collection, ok := collections[s.Slug]
+		if !ok {
+			var err error
+			if collection, err = repo.GetCollectionInfo(s.Slug); err != nil {
+				return nil, err
+			}
+			collections[s.Slug] = collection
+		}
+
+		cite, err := citation.Format(citation.StyleHR, collection, s.Hadith.Number, "")
+		if err != nil {
+			return nil, err
+		}
+
+		name := collection.Name
+		if name == "" {
+			name = collection.Slug
+		}
+
+		entries = append(entries, booklet.Entry{
+			Slug:       s.Slug,
+			Collection: name,
+			Citation:   fmt.Sprint(cite.Citation),
+			Hadith:     *s.Hadith,
+		})
+	}
+
+	return entries, nil
+}
+
+// selectHadiths pairs each hadith with its narrator
+func selectHadiths(narrator string, hadiths []models.Hadith) []models.SelectedHadith {
+	selected := make([]models.SelectedHadith, len(hadiths))
+	for i := range hadiths {
+		selected[i] = models.SelectedHadith{Slug: narrator, Hadith: &hadiths[i]}
+	}
+	return selected
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/repository"
+)
+
+func TestExportBookletSelection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewHadithHandler(repository.NewFileRepository("../repository/testdata/data"))
+	router.GET("/export/:slug", handler.ExportCollection)
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/export/alpha.pdf?refs=1,2a,beta:3", http.StatusOK},
+		{"/export/alpha.epub?range=1-3", http.StatusOK},
+		// Malformed references are bad requests, missing hadiths are not found
+		{"/export/alpha.pdf?refs=1,x", http.StatusBadRequest},
+		{"/export/alpha.pdf?refs=1,0", http.StatusBadRequest},
+		{"/export/alpha.pdf?refs=1,99", http.StatusNotFound},
+		{"/export/alpha.pdf?refs=1,gamma:1", http.StatusNotFound},
+		// The whole collection, or more than maxBatchSize hadiths, is not laid out
+		{"/export/alpha.pdf", http.StatusBadRequest},
+		{"/export/alpha.pdf?range=1-101", http.StatusBadRequest},
+		{"/export/alpha.pdf?range=1-x", http.StatusBadRequest},
+		{"/export/gamma.pdf?range=1-3", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.code {
+			t.Errorf("GET %s code = %d, want %d: %s", tt.path, w.Code, tt.code, w.Body.String())
+		}
+	}
+}
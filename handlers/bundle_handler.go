@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/bundle"
+	"github.com/hadith-api/models"
+)
+
+// bundleDirs matches the temporary directories bundles are compiled into
+const bundleDirs = "hadith-bundle*"
+
+// staleBundleAge is how long a bundle directory is left alone after it was
+// last modified, so that builds running in other processes can finish
+const staleBundleAge = 10 * time.Minute
+
+// bundleCache holds the offline bundle compiled by this process. The data
+// does not change while the process runs, so it is built on first request only.
+type bundleCache struct {
+	mu   sync.Mutex
+	path string
+	info *bundle.Info
+}
+
+// GetBundle godoc
+// @Summary      Download the offline bundle
+// @Description  Returns every collection compiled into a single SQLite file with collection metadata, a schema_version table and an FTS5 index on the normalized Arabic text and translation. The ETag is the dataset version, so clients can check for updates with If-None-Match.
+// @Tags         export
+// @Produce      application/vnd.sqlite3
+// @Success      200  {file}    file
+// @Success      304  "Bundle unchanged"
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /bundle [get]
+func (h *HadithHandler) GetBundle(c *gin.Context) {
+	path, info, err := h.buildBundle()
+	if err != nil {
+		respond(c, http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to build bundle",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		respond(c, http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to read bundle",
+			Error:   err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	name := fmt.Sprintf("hadith-v%d-%s.sqlite", info.SchemaVersion, info.Dataset)
+	c.Header("Content-Type", bundle.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, name))
+	c.Header("ETag", fmt.Sprintf(`"%d-%s"`, info.SchemaVersion, info.Dataset))
+
+	// ServeContent answers conditional and range requests, so downloads can resume
+	http.ServeContent(c.Writer, c.Request, name, info.CreatedAt, file)
+}
+
+// buildBundle returns the bundle file of this process, compiling it into the
+// temporary directory the first time, or again when the file was removed
+func (h *HadithHandler) buildBundle() (string, *bundle.Info, error) {
+	h.bundle.mu.Lock()
+	defer h.bundle.mu.Unlock()
+
+	if h.bundle.info != nil {
+		if _, err := os.Stat(h.bundle.path); err == nil {
+			return h.bundle.path, h.bundle.info, nil
+		}
+	}
+
+	if h.bundle.path != "" {
+		os.RemoveAll(filepath.Dir(h.bundle.path))
+	}
+	removeStaleBundles()
+
+	dir, err := os.MkdirTemp("", bundleDirs)
+	if err != nil {
+		return "", nil, err
+	}
+	path := filepath.Join(dir, "hadith.sqlite")
+
+	info, err := bundle.Build(path, h.repo)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+
+	h.bundle.path, h.bundle.info = path, info
+	return path, info, nil
+}
+
+// removeStaleBundles removes the bundle directories left in the temporary
+// directory by earlier processes. A process whose bundle is removed compiles
+// it again on its next request.
+func removeStaleBundles() {
+	dirs, err := filepath.Glob(filepath.Join(os.TempDir(), bundleDirs))
+	if err != nil {
+		return
+	}
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err == nil && time.Since(info.ModTime()) > staleBundleAge {
+			os.RemoveAll(dir)
+		}
+	}
+}
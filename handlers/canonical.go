@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CanonicalNarrator redirects requests whose slug is an alias or differs in case
+// from the canonical narrator slug, e.g. /hadis/Muwatta/12 to /hadis/malik/12.
+// Unknown slugs are passed through so the handler can report them as not found.
+func (h *HadithHandler) CanonicalNarrator(c *gin.Context) {
+	slug := c.Param("slug")
+
+	canonical, err := h.repoFor(c).ResolveNarrator(slug)
+	if err != nil || canonical == slug {
+		c.Next()
+		return
+	}
+
+	// Rebuild the path from the route template, substituting the canonical slug
+	segments := strings.Split(c.FullPath(), "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		if segment == ":slug" {
+			segments[i] = canonical
+		} else {
+			segments[i] = c.Param(segment[1:])
+		}
+	}
+
+	location := strings.Join(segments, "/")
+	if c.Request.URL.RawQuery != "" {
+		location += "?" + c.Request.URL.RawQuery
+	}
+
+	c.Redirect(http.StatusMovedPermanently, location)
+	c.Abort()
+}
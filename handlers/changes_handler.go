@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/models"
+)
+
+// GetChanges godoc
+// @Summary      Get changes since a revision
+// @Description  Returns the hadiths added, modified and removed since a dataset revision, so offline clients can catch up without downloading everything again. Added and modified hadiths carry their full content and hash. Revision 0 is the empty dataset.
+// @Tags         sync
+// @Produce      json,xml,plain,application/msgpack
+// @Param        since  query     int  false "Revision the client has (default: 0)"
+// @Success      200    {object}  models.HadithResponse{data=models.ChangeSet}
+// @Failure      400    {object}  models.ErrorResponse
+// @Failure      404    {object}  models.ErrorResponse
+// @Failure      500    {object}  models.ErrorResponse
+// @Router       /changes [get]
+func (h *HadithHandler) GetChanges(c *gin.Context) {
+	since, err := strconv.Atoi(c.DefaultQuery("since", "0"))
+	if err != nil || since < 0 {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid revision",
+			Error:   "since must be a revision number",
+		})
+		return
+	}
+
+	revision, err := h.repoFor(c).GetRevision()
+	if err != nil {
+		respond(c, http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to get revision",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if since > revision.Revision {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid revision",
+			Error:   fmt.Sprintf("since is ahead of the current revision %d", revision.Revision),
+		})
+		return
+	}
+
+	changes, err := h.repoFor(c).GetChanges(since)
+	if err != nil {
+		respond(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "Revision not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, models.HadithResponse{
+		Status:  "success",
+		Message: "Changes retrieved successfully",
+		Data:    changes,
+	})
+}
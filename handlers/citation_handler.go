@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/citation"
+	"github.com/hadith-api/config"
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/repository"
+)
+
+// GetCitation godoc
+// @Summary      Get a formatted citation of a hadith
+// @Description  Formats a citation of a hadith using the collection's name, title and compiler
+// @Tags         hadiths
+// @Produce      json,xml,plain,application/msgpack
+// @Param        slug    path      string  true  "Narrator slug (e.g., muslim, bukhari)"
+// @Param        number  path      string  true  "Hadith number, optionally with a sub-letter (e.g., 12 or 12a)"
+// @Param        style   query     string  false "Citation style: hr, academic, bibtex or csl-json (default: hr)"
+// @Param        scheme  query     string  false "Numbering scheme of the given number (default: default)"
+// @Success      200     {object}  models.HadithResponse{data=models.Citation}
+// @Failure      400     {object}  models.ErrorResponse
+// @Failure      404     {object}  models.ErrorResponse
+// @Router       /hadis/{slug}/{number}/cite [get]
+func (h *HadithHandler) GetCitation(c *gin.Context) {
+	narrator := c.Param("slug")
+	scheme := c.DefaultQuery("scheme", models.DefaultScheme)
+	style := c.DefaultQuery("style", citation.StyleHR)
+
+	number, err := models.ParseNumber(c.Param("number"))
+	if err != nil {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid hadith number",
+			Error:   "Hadith number must be an integer, optionally followed by a sub-letter (e.g., 12a)",
+		})
+		return
+	}
+
+	hadith, err := h.repoFor(c).GetHadithByScheme(narrator, scheme, number)
+	if err != nil {
+		respond(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "Hadith not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	cited, err := h.citeHadith(h.repoFor(c), narrator, hadith, style)
+	if err != nil {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to format citation",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, models.HadithResponse{
+		Status:  "success",
+		Message: "Citation formatted successfully",
+		Data:    cited,
+	})
+}
+
+// citeHadith formats a citation of a hadith from the given narrator's collection
+func (h *HadithHandler) citeHadith(repo repository.Repository, narrator string, hadith *models.Hadith, style string) (*models.Citation, error) {
+	collection, err := repo.GetCollectionInfo(narrator)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/hadis/%s/%s", config.GetConfig().BaseURL, narrator, hadith.Number)
+	return citation.Format(style, collection, hadith.Number, url)
+}
+
+// includes reports whether the comma-separated include query parameter lists the given value
+func includes(c *gin.Context, value string) bool {
+	for _, include := range strings.Split(c.Query("include"), ",") {
+		if strings.TrimSpace(include) == value {
+			return true
+		}
+	}
+	return false
+}
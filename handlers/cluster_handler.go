@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/models"
+)
+
+// GetClusters godoc
+// @Summary      Get thematic clusters
+// @Description  Returns the clusters found by the offline clustering job with their size and top keywords
+// @Tags         clusters
+// @Produce      json,xml,plain,application/msgpack
+// @Success      200  {object}  models.HadithResponse{data=[]models.Cluster}
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /clusters [get]
+func (h *HadithHandler) GetClusters(c *gin.Context) {
+	clusters, err := h.repoFor(c).GetClusters()
+	if err != nil {
+		respond(c, http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to get clusters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, models.HadithResponse{
+		Status:  "success",
+		Message: "Clusters retrieved successfully",
+		Data:    clusters,
+	})
+}
+
+// GetHadithsByCluster godoc
+// @Summary      Get hadiths by cluster
+// @Description  Returns the hadiths assigned to a cluster across all narrators, with pagination
+// @Tags         clusters
+// @Produce      json,xml,plain,application/msgpack
+// @Param        id     path      int  true  "Cluster ID"
+// @Param        page   query     int  false "Page number for pagination (default: 1)"
+// @Param        limit  query     int  false "Items per page for pagination (default: 10)"
+// @Success      200    {object}  models.PaginatedResponse{data=[]models.SelectedHadith}
+// @Failure      400    {object}  models.ErrorResponse
+// @Failure      404    {object}  models.ErrorResponse
+// @Router       /clusters/{id}/hadis [get]
+func (h *HadithHandler) GetHadithsByCluster(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid cluster ID",
+			Error:   "Cluster ID must be an integer",
+		})
+		return
+	}
+
+	// Parse query parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	// Set default pagination values
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	cluster, err := h.repoFor(c).GetCluster(id)
+	if err != nil {
+		respond(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "Cluster not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// List the members in narrator order so that pages are stable
+	narrators := make([]string, 0, len(cluster.Hadiths))
+	for narrator := range cluster.Hadiths {
+		narrators = append(narrators, narrator)
+	}
+	sort.Strings(narrators)
+
+	var refs []models.HadithReference
+	for _, narrator := range narrators {
+		for _, number := range cluster.Hadiths[narrator] {
+			refs = append(refs, models.HadithReference{Slug: narrator, Number: number})
+		}
+	}
+
+	// Apply pagination before loading the hadiths
+	totalItems := len(refs)
+	startIndex := (page - 1) * limit
+	endIndex := startIndex + limit
+
+	if startIndex >= totalItems {
+		refs = nil
+	} else {
+		if endIndex > totalItems {
+			endIndex = totalItems
+		}
+		refs = refs[startIndex:endIndex]
+	}
+
+	hadiths := []models.SelectedHadith{}
+	for _, ref := range refs {
+		hadith, err := h.repoFor(c).GetHadithByNumber(ref.Slug, ref.Number)
+		if err != nil {
+			continue
+		}
+		hadiths = append(hadiths, models.SelectedHadith{Slug: ref.Slug, Hadith: hadith})
+	}
+
+	// Calculate pagination values
+	totalPages := (totalItems + limit - 1) / limit // Ceiling division
+
+	respond(c, http.StatusOK, models.PaginatedResponse{
+		Status:  "success",
+		Message: "Hadiths retrieved successfully",
+		Data:    hadiths,
+		Pagination: models.Pagination{
+			CurrentPage: page,
+			TotalItems:  totalItems,
+			TotalPages:  totalPages,
+			PerPage:     limit,
+		},
+	})
+}
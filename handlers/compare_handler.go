@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/worddiff"
+)
+
+// comparisonTemplate renders a comparison as a standalone HTML page, with
+// deleted words struck through and inserted words highlighted
+var comparisonTemplate = template.Must(template.New("compare").Funcs(template.FuncMap{
+	"percent": func(f float64) float64 { return f * 100 },
+}).Parse(`<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<title>{{.A.Slug}} {{.A.Hadith.Number}} / {{.B.Slug}} {{.B.Hadith.Number}}</title>
+<style>
+body { font-family: sans-serif; max-width: 60em; margin: 2em auto; line-height: 1.6; }
+.arab { font-size: 1.4em; direction: rtl; text-align: right; }
+del { background: #fdd; color: #900; }
+ins { background: #dfd; color: #060; text-decoration: none; }
+</style>
+</head>
+<body>
+<h1>{{.A.Slug}} {{.A.Hadith.Number}} &harr; {{.B.Slug}} {{.B.Hadith.Number}}</h1>
+<h2>Arab ({{printf "%.0f" (percent .Arabic.Similarity)}}%)</h2>
+<p class="arab" dir="rtl" lang="ar">{{range .Arabic.Ops}}{{template "op" .}} {{end}}</p>
+<h2>Terjemahan ({{printf "%.0f" (percent .Translation.Similarity)}}%)</h2>
+<p>{{range .Translation.Ops}}{{template "op" .}} {{end}}</p>
+</body>
+</html>
+{{define "op"}}{{if eq .Op "equal"}}{{.A}}{{else if eq .Op "delete"}}<del>{{.A}}</del>{{else}}<ins>{{.B}}</ins>{{end}}{{end}}`))
+
+// CompareHadiths godoc
+// @Summary      Compare two hadiths word by word
+// @Description  Returns a word-level diff of the Arabic text and of the translation of two hadiths, as JSON or another negotiated format, or as an HTML page
+// @Tags         analysis
+// @Produce      json,xml,plain,application/msgpack,html
+// @Param        a           query     string  true  "First hadith as slug:number (e.g., malik:12)"
+// @Param        b           query     string  true  "Second hadith as slug:number (e.g., darimi:34)"
+// @Param        diacritics  query     string  false "normalize (default) to ignore Arabic diacritics, or preserve to compare them"
+// @Param        format      query     string  false "json, xml, msgpack, text or html (default: negotiated from Accept)"
+// @Success      200         {object}  models.HadithResponse{data=models.Comparison}
+// @Failure      400         {object}  models.ErrorResponse
+// @Failure      404         {object}  models.ErrorResponse
+// @Router       /compare [get]
+func (h *HadithHandler) CompareHadiths(c *gin.Context) {
+	// The HTML page is not a response format of respond, so its errors are negotiated
+	reply := respond
+	switch c.Query("format") {
+	case "", "json", "xml", "msgpack", "text":
+	case "html":
+		reply = respondNegotiated
+	default:
+		respondNegotiated(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid format",
+			Error:   "format must be json, xml, msgpack, text or html",
+		})
+		return
+	}
+
+	diacritics := c.DefaultQuery("diacritics", "normalize")
+	arabicKey := worddiff.Normalized
+	switch diacritics {
+	case "normalize":
+	case "preserve":
+		arabicKey = worddiff.Preserved
+	default:
+		reply(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid diacritics option",
+			Error:   "diacritics must be normalize or preserve",
+		})
+		return
+	}
+
+	sides := make([]models.SelectedHadith, 2)
+	for i, param := range []string{"a", "b"} {
+		slug, number, ok := strings.Cut(c.Query(param), ":")
+		if !ok || slug == "" {
+			reply(c, http.StatusBadRequest, models.ErrorResponse{
+				Status:  "error",
+				Message: "Invalid hadith reference",
+				Error:   fmt.Sprintf("%s must be given as slug:number", param),
+			})
+			return
+		}
+
+		n, err := models.ParseNumber(number)
+		if err != nil {
+			reply(c, http.StatusBadRequest, models.ErrorResponse{
+				Status:  "error",
+				Message: "Invalid hadith number",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		narrator, err := h.repoFor(c).ResolveNarrator(slug)
+		if err != nil {
+			reply(c, http.StatusNotFound, models.ErrorResponse{
+				Status:  "error",
+				Message: "Narrator not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		hadith, err := h.repoFor(c).GetHadithByNumber(narrator, n)
+		if err != nil {
+			reply(c, http.StatusNotFound, models.ErrorResponse{
+				Status:  "error",
+				Message: "Hadith not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		sides[i] = models.SelectedHadith{Slug: narrator, Hadith: hadith}
+	}
+
+	comparison := models.Comparison{
+		A:           sides[0],
+		B:           sides[1],
+		Diacritics:  diacritics,
+		Arabic:      worddiff.Diff(sides[0].Hadith.Arab, sides[1].Hadith.Arab, arabicKey),
+		Translation: worddiff.Diff(sides[0].Hadith.ID, sides[1].Hadith.ID, worddiff.Normalized),
+	}
+
+	if c.Query("format") == "html" {
+		var page bytes.Buffer
+		if err := comparisonTemplate.Execute(&page, comparison); err != nil {
+			reply(c, http.StatusInternalServerError, models.ErrorResponse{
+				Status:  "error",
+				Message: "Failed to render comparison",
+				Error:   err.Error(),
+			})
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+		return
+	}
+
+	reply(c, http.StatusOK, models.HadithResponse{
+		Status:  "success",
+		Message: "Comparison retrieved successfully",
+		Data:    comparison,
+	})
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/config"
+	"github.com/hadith-api/daily"
+	"github.com/hadith-api/hijri"
+	"github.com/hadith-api/models"
+)
+
+// maxDailyYear bounds the dates accepted by the daily endpoint
+const maxDailyYear = 2100
+
+// GetRandomHadith godoc
+// @Summary      Get a random hadith
+// @Description  Returns a random hadith, optionally restricted by narrator, grade and translation length
+// @Tags         hadiths
+// @Produce      json,xml,plain,application/msgpack
+// @Param        narrator    query     string  false "Narrator slug to pick from"
+// @Param        grade       query     string  false "Filter by grade (sahih, hasan, daif, maudu, ungraded)"
+// @Param        min_length  query     int     false "Minimum length of the translation in characters"
+// @Param        max_length  query     int     false "Maximum length of the translation in characters"
+// @Success      200         {object}  models.HadithResponse{data=models.SelectedHadith}
+// @Failure      400         {object}  models.ErrorResponse
+// @Failure      404         {object}  models.ErrorResponse
+// @Failure      500         {object}  models.ErrorResponse
+// @Router       /hadis/random [get]
+func (h *HadithHandler) GetRandomHadith(c *gin.Context) {
+	grade := c.Query("grade")
+	minLength, errMin := strconv.Atoi(c.DefaultQuery("min_length", "0"))
+	maxLength, errMax := strconv.Atoi(c.DefaultQuery("max_length", "0"))
+	if errMin != nil || errMax != nil || minLength < 0 || maxLength < 0 {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid length filter",
+			Error:   "min_length and max_length must be non-negative integers",
+		})
+		return
+	}
+
+	var narrators []string
+	if narrator := c.Query("narrator"); narrator != "" {
+		resolved, err := h.repoFor(c).ResolveNarrator(narrator)
+		if err != nil {
+			respond(c, http.StatusNotFound, models.ErrorResponse{
+				Status:  "error",
+				Message: "Narrator not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+		narrators = []string{resolved}
+	} else {
+		var err error
+		narrators, err = h.repoFor(c).GetAvailableNarrators()
+		if err != nil {
+			respond(c, http.StatusInternalServerError, models.ErrorResponse{
+				Status:  "error",
+				Message: "Failed to get narrators",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	// Collect every hadith matching the filters
+	var candidates []models.SelectedHadith
+	for _, narrator := range narrators {
+		hadiths, _, err := h.repoFor(c).GetHadithsByNarrator(narrator, models.QueryParams{Grade: grade})
+		if err != nil {
+			continue
+		}
+
+		for i := range hadiths {
+			length := utf8.RuneCountInString(hadiths[i].ID)
+			if length < minLength || (maxLength > 0 && length > maxLength) {
+				continue
+			}
+			candidates = append(candidates, models.SelectedHadith{Slug: narrator, Hadith: &hadiths[i]})
+		}
+	}
+
+	if len(candidates) == 0 {
+		respond(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "Hadith not found",
+			Error:   "No hadith matches the given filters",
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, models.HadithResponse{
+		Status:  "success",
+		Message: "Random hadith retrieved successfully",
+		Data:    candidates[rand.Intn(len(candidates))],
+	})
+}
+
+// GetDailyHadith godoc
+// @Summary      Get the hadith of the day
+// @Description  Returns the hadith of the day with its Hijri date. The selection depends only on the date and the corpus, so every client gets the same hadith on the same date, and hadiths are not repeated within the configured window. During occasions such as Ramadan, Dhul Hijjah and Jumu'ah the hadith is taken from the occasion's configured list.
+// @Tags         hadiths
+// @Produce      json,xml,plain,application/msgpack
+// @Param        date       query     string  false "Date in YYYY-MM-DD format (default: today in the given time zone)"
+// @Param        tz         query     string  false "IANA time zone used to determine today's date (default: UTC)"
+// @Param        occasions  query     bool    false "Set to false to ignore occasion-specific selections (default: true)"
+// @Success      200        {object}  models.HadithResponse{data=models.DailyHadith}
+// @Failure      400        {object}  models.ErrorResponse
+// @Failure      500        {object}  models.ErrorResponse
+// @Router       /hadis/daily [get]
+func (h *HadithHandler) GetDailyHadith(c *gin.Context) {
+	tz := c.DefaultQuery("tz", "UTC")
+	location, err := time.LoadLocation(tz)
+	if err != nil {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid time zone",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	date := time.Now().In(location)
+	if dateStr := c.Query("date"); dateStr != "" {
+		date, err = time.ParseInLocation("2006-01-02", dateStr, location)
+		if err != nil || date.Year() > maxDailyYear {
+			respond(c, http.StatusBadRequest, models.ErrorResponse{
+				Status:  "error",
+				Message: "Invalid date",
+				Error:   "Date must be in YYYY-MM-DD format and no later than " + strconv.Itoa(maxDailyYear),
+			})
+			return
+		}
+	}
+
+	refs, fingerprint, err := h.repoFor(c).GetCorpus()
+	if err != nil || len(refs) == 0 {
+		message := "No hadiths available"
+		if err != nil {
+			message = err.Error()
+		}
+		respond(c, http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to select daily hadith",
+			Error:   message,
+		})
+		return
+	}
+
+	hijriDate := hijri.FromGregorian(date, config.GetConfig().HijriAdjustment)
+
+	// Occasions such as Ramadan or Friday take their hadith from a configured list
+	var occasionName string
+	ref := refs[h.daily.Pick(date, len(refs), fingerprint)]
+	if c.DefaultQuery("occasions", "true") != "false" {
+		occasions, err := h.repoFor(c).GetOccasions()
+		if err != nil {
+			respond(c, http.StatusInternalServerError, models.ErrorResponse{
+				Status:  "error",
+				Message: "Failed to get occasions",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		if occasion := daily.MatchOccasion(occasions, date, hijriDate); occasion != nil {
+			narrators := make(map[string]bool)
+			for _, r := range refs {
+				narrators[r.Slug] = true
+			}
+			available := func(slug string) bool { return narrators[slug] }
+			if picked, ok := daily.PickOccasion(occasion, date, hijriDate, fingerprint, available); ok {
+				ref = picked
+				occasionName = occasion.Name
+			}
+		}
+	}
+
+	hadith, err := h.repoFor(c).GetHadithByNumber(ref.Slug, ref.Number)
+	if err != nil {
+		respond(c, http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to select daily hadith",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, models.HadithResponse{
+		Status:  "success",
+		Message: "Daily hadith retrieved successfully",
+		Data: models.DailyHadith{
+			Date:     date.Format("2006-01-02"),
+			Timezone: location.String(),
+			Hijri: models.HijriDate{
+				Year:            hijriDate.Year,
+				Month:           hijriDate.Month,
+				Day:             hijriDate.Day,
+				MonthName:       hijriDate.MonthName(),
+				MonthNameArabic: hijriDate.MonthNameArabic(),
+				Formatted:       hijriDate.String(),
+			},
+			Occasion: occasionName,
+			Slug:     ref.Slug,
+			Hadith:   hadith,
+		},
+	})
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/anki"
+	"github.com/hadith-api/citation"
+	"github.com/hadith-api/models"
+)
+
+// ExportDeck godoc
+// @Summary      Export an Anki flashcard deck
+// @Description  Generates an Anki deck (.apkg) for memorization with the Arabic text on the front of each card and the translation and reference on the back. The deck holds the hadiths listed by /hadis/{slug} with the same search, grade and topic filters, optionally narrowed to a range of numbers. With cloze set, the matn is split into cloze deletions with a card for each.
+// @Tags         export
+// @Produce      application/apkg
+// @Param        slug   path      string  true  "Narrator slug (e.g., muslim, bukhari)"
+// @Param        range  query     string  false "Range of hadith numbers (e.g., 1-40)"
+// @Param        topic  query     string  false "Topic slug (e.g., prayer)"
+// @Param        q      query     string  false "Search query to filter hadiths"
+// @Param        grade  query     string  false "Filter by grade (sahih, hasan, daif, maudu or ungraded)"
+// @Param        cloze  query     int     false "Words per cloze deletion on the matn (default: 0, no cloze)"
+// @Success      200    {file}    file
+// @Failure      400    {object}  models.ErrorResponse
+// @Failure      404    {object}  models.ErrorResponse
+// @Router       /export/{slug}.apkg [get]
+func (h *HadithHandler) ExportDeck(c *gin.Context) {
+	name := strings.TrimSuffix(c.Param("slug"), ".apkg")
+
+	narrator, err := h.repoFor(c).ResolveNarrator(name)
+	if err != nil {
+		respondNegotiated(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "Narrator not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	collection, err := h.repoFor(c).GetCollectionInfo(narrator)
+	if err != nil {
+		respondNegotiated(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "Collection not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	cloze, _ := strconv.Atoi(c.DefaultQuery("cloze", "0"))
+	if cloze < 0 {
+		cloze = 0
+	}
+
+	params := models.QueryParams{
+		Query: c.Query("q"),
+		Grade: c.Query("grade"),
+		Topic: c.Query("topic"),
+	}
+
+	deckName := collection.Title
+	if deckName == "" {
+		deckName = collection.Name
+	}
+	deckName = "Hadits::" + deckName
+	filename := narrator
+
+	if params.Topic != "" {
+		topic, err := h.repoFor(c).GetTopic(params.Topic)
+		if err != nil {
+			respondNegotiated(c, http.StatusNotFound, models.ErrorResponse{
+				Status:  "error",
+				Message: "Topic not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+		deckName += "::" + topic.Name
+		filename += "-" + topic.Slug
+	}
+
+	hadiths, _, err := h.repoFor(c).GetHadithsByNarrator(narrator, params)
+	if err != nil {
+		respondNegotiated(c, http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to get hadiths",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if numberRange := c.Query("range"); numberRange != "" {
+		from, to, err := parseRange(numberRange)
+		if err != nil {
+			respondNegotiated(c, http.StatusBadRequest, models.ErrorResponse{
+				Status:  "error",
+				Message: "Invalid range",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		var inRange []models.Hadith
+		for _, hadith := range hadiths {
+			if !hadith.Number.Less(from) && !to.Less(hadith.Number) {
+				inRange = append(inRange, hadith)
+			}
+		}
+		hadiths = inRange
+		deckName += fmt.Sprintf("::No. %s–%s", from, to)
+		filename += fmt.Sprintf("-%s-%s", from, to)
+	}
+
+	if len(hadiths) == 0 {
+		respondNegotiated(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "No hadiths found",
+			Error:   "the selection is empty",
+		})
+		return
+	}
+
+	deck := &anki.Deck{Name: deckName, Cloze: cloze}
+	for _, hadith := range hadiths {
+		cite, err := citation.Format(citation.StyleHR, collection, hadith.Number, "")
+		if err != nil {
+			respondNegotiated(c, http.StatusInternalServerError, models.ErrorResponse{
+				Status:  "error",
+				Message: "Failed to prepare deck",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		tags := []string{narrator}
+		for _, tag := range hadith.Tags {
+			tags = append(tags, tag.Topic)
+		}
+
+		deck.Notes = append(deck.Notes, anki.Note{
+			ID:          fmt.Sprintf("%s:%s", narrator, hadith.Number),
+			Arabic:      hadith.Arab,
+			Translation: hadith.ID,
+			Reference:   fmt.Sprint(cite.Citation),
+			Tags:        tags,
+		})
+	}
+
+	// The deck is built in full first, so a failure can still be reported
+	var buf bytes.Buffer
+	if err := anki.Write(&buf, deck); err != nil {
+		respondNegotiated(c, http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to write deck",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.apkg"`, filename))
+	c.Data(http.StatusOK, anki.ContentType, buf.Bytes())
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/booklet"
+	"github.com/hadith-api/export"
+	"github.com/hadith-api/models"
+)
+
+// exportFlushInterval is the number of hadiths written between flushes to the client
+const exportFlushInterval = 100
+
+// ExportCollection godoc
+// @Summary      Export a collection
+// @Description  Streams every hadith of a narrator's collection as NDJSON, CSV (UTF-8 with BOM) or a JSON array
+// @Tags         export
+// @Produce      json,plain
+// @Param        slug    path      string  true  "Narrator slug (e.g., muslim, bukhari)"
+// @Param        format  query     string  false "ndjson (default), csv or json"
+// @Success      200     {file}    file
+// @Failure      400     {object}  models.ErrorResponse
+// @Failure      404     {object}  models.ErrorResponse
+// @Router       /export/{slug} [get]
+func (h *HadithHandler) ExportCollection(c *gin.Context) {
+	narrator := c.Param("slug")
+
+	// Booklets and decks share the route, as /export/{slug}.pdf, .epub or .apkg
+	if _, _, ok := booklet.Split(narrator); ok {
+		h.ExportBooklet(c)
+		return
+	}
+	if strings.HasSuffix(narrator, ".apkg") {
+		h.ExportDeck(c)
+		return
+	}
+
+	if _, err := h.repoFor(c).GetCollectionInfo(narrator); err != nil {
+		respondNegotiated(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "Collection not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	h.streamExport(c, narrator, []string{narrator}, false)
+}
+
+// ExportCorpus godoc
+// @Summary      Export all collections
+// @Description  Streams every hadith of every narrator, each with its narrator slug, as NDJSON, CSV (UTF-8 with BOM) or a JSON array
+// @Tags         export
+// @Produce      json,plain
+// @Param        format  query     string  false "ndjson (default), csv or json"
+// @Success      200     {file}    file
+// @Failure      400     {object}  models.ErrorResponse
+// @Failure      500     {object}  models.ErrorResponse
+// @Router       /export [get]
+func (h *HadithHandler) ExportCorpus(c *gin.Context) {
+	narrators, err := h.repoFor(c).GetAvailableNarrators()
+	if err != nil {
+		respondNegotiated(c, http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to get narrators",
+			Error:   err.Error(),
+		})
+		return
+	}
+	sort.Strings(narrators)
+
+	h.streamExport(c, "hadith", narrators, true)
+}
+
+// streamExport writes the hadiths of the narrators in the requested format,
+// flushing to the client as it goes. Once streaming has started, errors can
+// no longer change the status, so they end the response early and are logged.
+func (h *HadithHandler) streamExport(c *gin.Context, name string, narrators []string, corpus bool) {
+	formatName := c.DefaultQuery("format", "ndjson")
+	format, ok := export.Formats[formatName]
+	if !ok {
+		respondNegotiated(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid format",
+			Error:   "format must be ndjson, csv or json",
+		})
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format.Extension))
+	c.Status(http.StatusOK)
+
+	writer := format.New(c.Writer, corpus)
+	written := 0
+	for _, narrator := range narrators {
+		hadiths, _, err := h.repoFor(c).GetHadithsByNarrator(narrator, models.QueryParams{})
+		if err != nil {
+			log.Printf("Export of %s stopped: %v", name, err)
+			return
+		}
+
+		for _, hadith := range hadiths {
+			if err := writer.WriteHadith(narrator, hadith); err != nil {
+				log.Printf("Export of %s stopped: %v", name, err)
+				return
+			}
+
+			written++
+			if written%exportFlushInterval == 0 {
+				if err := writer.Flush(); err != nil {
+					log.Printf("Export of %s stopped: %v", name, err)
+					return
+				}
+				c.Writer.Flush()
+			}
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Printf("Export of %s stopped: %v", name, err)
+	}
+	c.Writer.Flush()
+}
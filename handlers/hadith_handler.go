@@ -3,32 +3,48 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/citation"
+	"github.com/hadith-api/config"
+	"github.com/hadith-api/daily"
 	"github.com/hadith-api/models"
 	"github.com/hadith-api/repository"
 )
 
 // HadithHandler handles HTTP requests related to hadiths
 type HadithHandler struct {
-	repo *repository.FileRepository
+	repo   repository.Repository
+	daily  *daily.Selector
+	bundle *bundleCache
 }
 
 // NewHadithHandler creates a new HadithHandler with the given repository
-func NewHadithHandler(repo *repository.FileRepository) *HadithHandler {
+func NewHadithHandler(repo repository.Repository) *HadithHandler {
 	return &HadithHandler{
-		repo: repo,
+		repo:   repo,
+		daily:  daily.NewSelector(config.GetConfig().DailyRepeatWindow),
+		bundle: &bundleCache{},
 	}
 }
 
+// repoFor returns the repository with its queries bound to the request's
+// context, so that they stop when the client goes away
+func (h *HadithHandler) repoFor(c *gin.Context) repository.Repository {
+	return h.repo.WithContext(c.Request.Context())
+}
+
 // GetAllHadiths godoc
 // @Summary      Get all hadiths
 // @Description  Returns all hadiths with pagination and optional search filtering
 // @Tags         hadiths
-// @Produce      json
+// @Produce      json,xml,plain,application/msgpack
 // @Param        page   query     int     false "Page number for pagination (default: 1)"
 // @Param        limit  query     int     false "Items per page for pagination (default: 10)"
 // @Param        q      query     string  false "Search query to filter hadiths by ID (translation)"
+// @Param        grade  query     string  false "Filter by grade (sahih, hasan, daif, maudu, ungraded)"
+// @Param        topic  query     string  false "Filter by topic slug (e.g., prayer, fasting)"
 // @Success      200    {object}  models.PaginatedResponse
 // @Failure      500    {object}  models.ErrorResponse
 // @Router       /hadis [get]
@@ -37,6 +53,8 @@ func (h *HadithHandler) GetAllHadiths(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	query := c.Query("q")
+	grade := c.Query("grade")
+	topic := c.Query("topic")
 
 	// Set default pagination values
 	if page < 1 {
@@ -47,9 +65,9 @@ func (h *HadithHandler) GetAllHadiths(c *gin.Context) {
 	}
 
 	// Get all hadiths with pagination and filtering
-	narrators, err := h.repo.GetAvailableNarrators()
+	narrators, err := h.repoFor(c).GetAvailableNarrators()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		respond(c, http.StatusInternalServerError, models.ErrorResponse{
 			Status:  "error",
 			Message: "Failed to get narrators",
 			Error:   err.Error(),
@@ -60,11 +78,13 @@ func (h *HadithHandler) GetAllHadiths(c *gin.Context) {
 	var allHadiths []models.Hadith
 	var totalItems int
 
-	// If a query is provided, search across all narrator collections
-	if query != "" {
+	// If a query, grade or topic is provided, search across all narrator collections
+	if query != "" || grade != "" || topic != "" {
 		for _, narrator := range narrators {
-			hadiths, _, err := h.repo.GetHadithsByNarrator(narrator, models.QueryParams{
+			hadiths, _, err := h.repoFor(c).GetHadithsByNarrator(narrator, models.QueryParams{
 				Query: query,
+				Grade: grade,
+				Topic: topic,
 			})
 			if err == nil {
 				allHadiths = append(allHadiths, hadiths...)
@@ -104,7 +124,7 @@ func (h *HadithHandler) GetAllHadiths(c *gin.Context) {
 
 			// Adjust page/offset for pagination across narrators
 			if processedCount < (page-1)*limit {
-				_, total, err := h.repo.GetHadithsByNarrator(narrator, models.QueryParams{})
+				_, total, err := h.repoFor(c).GetHadithsByNarrator(narrator, models.QueryParams{})
 				if err == nil {
 					if processedCount+total <= (page-1)*limit {
 						// Skip this entire narrator
@@ -118,7 +138,7 @@ func (h *HadithHandler) GetAllHadiths(c *gin.Context) {
 				}
 			}
 
-			hadiths, total, err := h.repo.GetHadithsByNarrator(narrator, narratorParams)
+			hadiths, total, err := h.repoFor(c).GetHadithsByNarrator(narrator, narratorParams)
 			if err == nil {
 				allHadiths = append(allHadiths, hadiths...)
 				totalItems += total
@@ -130,7 +150,7 @@ func (h *HadithHandler) GetAllHadiths(c *gin.Context) {
 	// Calculate pagination values
 	totalPages := (totalItems + limit - 1) / limit // Ceiling division
 
-	c.JSON(http.StatusOK, models.PaginatedResponse{
+	respond(c, http.StatusOK, models.PaginatedResponse{
 		Status:  "success",
 		Message: "All hadiths retrieved successfully",
 		Data:    allHadiths,
@@ -147,14 +167,14 @@ func (h *HadithHandler) GetAllHadiths(c *gin.Context) {
 // @Summary      Get list of available narrators
 // @Description  Returns a list of all available hadith narrators
 // @Tags         narrators
-// @Produce      json
+// @Produce      json,xml,plain,application/msgpack
 // @Success      200  {object}  models.HadithResponse
 // @Failure      500  {object}  models.ErrorResponse
 // @Router       /narrators [get]
 func (h *HadithHandler) GetNarrators(c *gin.Context) {
-	narrators, err := h.repo.GetAvailableNarrators()
+	narrators, err := h.repoFor(c).GetAvailableNarrators()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		respond(c, http.StatusInternalServerError, models.ErrorResponse{
 			Status:  "error",
 			Message: "Failed to get narrators",
 			Error:   err.Error(),
@@ -162,7 +182,7 @@ func (h *HadithHandler) GetNarrators(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.HadithResponse{
+	respond(c, http.StatusOK, models.HadithResponse{
 		Status:  "success",
 		Message: "Narrators retrieved successfully",
 		Data: models.Narrators{
@@ -171,15 +191,46 @@ func (h *HadithHandler) GetNarrators(c *gin.Context) {
 	})
 }
 
+// GetCollection godoc
+// @Summary      Get collection metadata
+// @Description  Returns metadata for a narrator's collection, including a summary of hadith grades
+// @Tags         narrators
+// @Produce      json,xml,plain,application/msgpack
+// @Param        slug  path      string  true  "Narrator slug (e.g., muslim, bukhari)"
+// @Success      200   {object}  models.HadithResponse{data=models.Collection}
+// @Failure      404   {object}  models.ErrorResponse
+// @Router       /narrators/{slug} [get]
+func (h *HadithHandler) GetCollection(c *gin.Context) {
+	narrator := c.Param("slug")
+
+	collection, err := h.repoFor(c).GetCollection(narrator)
+	if err != nil {
+		respond(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "Collection not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, models.HadithResponse{
+		Status:  "success",
+		Message: "Collection retrieved successfully",
+		Data:    collection,
+	})
+}
+
 // GetHadithsByNarrator godoc
 // @Summary      Get hadiths by narrator
 // @Description  Returns all hadiths from a specific narrator with optional pagination and filtering
 // @Tags         hadiths
-// @Produce      json
+// @Produce      json,xml,plain,application/msgpack
 // @Param        slug   path      string  true  "Narrator slug (e.g., muslim, bukhari)"
 // @Param        page   query     int     false "Page number for pagination"
 // @Param        limit  query     int     false "Items per page for pagination"
 // @Param        q      query     string  false "Search query to filter hadiths"
+// @Param        grade  query     string  false "Filter by grade (sahih, hasan, daif, maudu, ungraded)"
+// @Param        topic  query     string  false "Filter by topic slug (e.g., prayer, fasting)"
 // @Success      200    {object}  models.PaginatedResponse
 // @Failure      404    {object}  models.ErrorResponse
 // @Failure      500    {object}  models.ErrorResponse
@@ -191,6 +242,8 @@ func (h *HadithHandler) GetHadithsByNarrator(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	query := c.Query("q")
+	grade := c.Query("grade")
+	topic := c.Query("topic")
 
 	// Set default pagination values
 	if page < 1 {
@@ -201,13 +254,15 @@ func (h *HadithHandler) GetHadithsByNarrator(c *gin.Context) {
 	}
 
 	// Get hadiths with pagination and filtering
-	hadiths, totalItems, err := h.repo.GetHadithsByNarrator(narrator, models.QueryParams{
+	hadiths, totalItems, err := h.repoFor(c).GetHadithsByNarrator(narrator, models.QueryParams{
 		Page:  page,
 		Limit: limit,
 		Query: query,
+		Grade: grade,
+		Topic: topic,
 	})
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
+		respond(c, http.StatusNotFound, models.ErrorResponse{
 			Status:  "error",
 			Message: "Failed to get hadiths",
 			Error:   err.Error(),
@@ -218,7 +273,7 @@ func (h *HadithHandler) GetHadithsByNarrator(c *gin.Context) {
 	// Calculate pagination values
 	totalPages := (totalItems + limit - 1) / limit // Ceiling division
 
-	c.JSON(http.StatusOK, models.PaginatedResponse{
+	respond(c, http.StatusOK, models.PaginatedResponse{
 		Status:  "success",
 		Message: "Hadiths retrieved successfully",
 		Data:    hadiths,
@@ -233,34 +288,45 @@ func (h *HadithHandler) GetHadithsByNarrator(c *gin.Context) {
 
 // GetHadithByNumber godoc
 // @Summary      Get hadith by narrator and number
-// @Description  Returns a specific hadith from a narrator by its number
+// @Description  Returns a specific hadith from a narrator by its number, optionally in an alternate numbering scheme. A range such as 1-40 returns up to 100 hadiths.
 // @Tags         hadiths
-// @Produce      json
+// @Produce      json,xml,plain,application/msgpack
 // @Param        slug    path      string  true  "Narrator slug (e.g., muslim, bukhari)"
-// @Param        number  path      int     true  "Hadith number"
+// @Param        number  path      string  true  "Hadith number, optionally with a sub-letter (e.g., 12 or 12a), or a range (e.g., 1-40)"
+// @Param        scheme  query     string  false "Numbering scheme of the given number (default: default)"
+// @Param        include query     string  false "Set to citation to include a formatted citation"
+// @Param        style   query     string  false "Citation style for include=citation: hr, academic, bibtex or csl-json (default: hr)"
 // @Success      200     {object}  models.HadithResponse
+// @Failure      400     {object}  models.ErrorResponse
 // @Failure      404     {object}  models.ErrorResponse
 // @Failure      500     {object}  models.ErrorResponse
 // @Router       /hadis/{slug}/{number} [get]
 func (h *HadithHandler) GetHadithByNumber(c *gin.Context) {
 	narrator := c.Param("slug")
 	numberStr := c.Param("number")
+	scheme := c.DefaultQuery("scheme", models.DefaultScheme)
+
+	// A "from-to" number retrieves a range of hadiths
+	if strings.Contains(numberStr, "-") {
+		h.getHadithRange(c, narrator, numberStr, scheme)
+		return
+	}
 
-	// Convert number string to int
-	number, err := strconv.Atoi(numberStr)
+	// Parse the hadith number
+	number, err := models.ParseNumber(numberStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
 			Status:  "error",
 			Message: "Invalid hadith number",
-			Error:   "Hadith number must be an integer",
+			Error:   "Hadith number must be an integer, optionally followed by a sub-letter (e.g., 12a)",
 		})
 		return
 	}
 
 	// Get the hadith
-	hadith, err := h.repo.GetHadithByNumber(narrator, number)
+	hadith, err := h.repoFor(c).GetHadithByScheme(narrator, scheme, number)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
+		respond(c, http.StatusNotFound, models.ErrorResponse{
 			Status:  "error",
 			Message: "Hadith not found",
 			Error:   err.Error(),
@@ -268,9 +334,74 @@ func (h *HadithHandler) GetHadithByNumber(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.HadithResponse{
+	// Include a formatted citation when requested
+	if includes(c, "citation") {
+		cited, err := h.citeHadith(h.repoFor(c), narrator, hadith, c.DefaultQuery("style", citation.StyleHR))
+		if err != nil {
+			respond(c, http.StatusBadRequest, models.ErrorResponse{
+				Status:  "error",
+				Message: "Failed to format citation",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		respond(c, http.StatusOK, models.HadithResponse{
+			Status:  "success",
+			Message: "Hadith retrieved successfully",
+			Data:    models.CitedHadith{Hadith: hadith, Citation: cited},
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, models.HadithResponse{
 		Status:  "success",
 		Message: "Hadith retrieved successfully",
 		Data:    hadith,
 	})
 }
+
+// GetConcordance godoc
+// @Summary      Get hadith numbers across numbering schemes
+// @Description  Translates a hadith number from one numbering scheme into the other schemes known for the collection
+// @Tags         hadiths
+// @Produce      json,xml,plain,application/msgpack
+// @Param        slug    path      string  true  "Narrator slug (e.g., muslim, bukhari)"
+// @Param        number  path      string  true  "Hadith number, optionally with a sub-letter (e.g., 12 or 12a)"
+// @Param        scheme  query     string  false "Numbering scheme of the given number (default: default)"
+// @Param        to      query     string  false "Only translate into this scheme"
+// @Success      200     {object}  models.HadithResponse{data=models.Concordance}
+// @Failure      400     {object}  models.ErrorResponse
+// @Failure      404     {object}  models.ErrorResponse
+// @Router       /hadis/{slug}/{number}/concordance [get]
+func (h *HadithHandler) GetConcordance(c *gin.Context) {
+	narrator := c.Param("slug")
+	scheme := c.DefaultQuery("scheme", models.DefaultScheme)
+	target := c.Query("to")
+
+	number, err := models.ParseNumber(c.Param("number"))
+	if err != nil {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid hadith number",
+			Error:   "Hadith number must be an integer, optionally followed by a sub-letter (e.g., 12a)",
+		})
+		return
+	}
+
+	concordance, err := h.repoFor(c).GetConcordance(narrator, scheme, number, target)
+	if err != nil {
+		respond(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to translate hadith number",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, models.HadithResponse{
+		Status:  "success",
+		Message: "Hadith numbers retrieved successfully",
+		Data:    concordance,
+	})
+}
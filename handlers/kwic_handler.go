@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/kwic"
+	"github.com/hadith-api/models"
+)
+
+// maxWindow bounds the number of context words on either side of a keyword
+const maxWindow = 20
+
+// GetKeywordInContext godoc
+// @Summary      Keyword-in-context concordance
+// @Description  Returns every occurrence of a term across the loaded collections with its left and right context. Words are compared after normalization, so a term without diacritics matches the vocalized Arabic text.
+// @Tags         analysis
+// @Produce      json,xml,plain,application/msgpack
+// @Param        term      query     string  true  "Word or phrase to find; a trailing * matches word prefixes"
+// @Param        window    query     int     false "Number of context words on either side (default: 5, max: 20)"
+// @Param        sort      query     string  false "Sort by left or right context (default: corpus order)"
+// @Param        field     query     string  false "Only search the arab or id text"
+// @Param        narrator  query     string  false "Only search this narrator's collection"
+// @Param        page      query     int     false "Page number for pagination (default: 1)"
+// @Param        limit     query     int     false "Items per page for pagination (default: 10)"
+// @Success      200       {object}  models.PaginatedResponse{data=[]models.KWICLine}
+// @Failure      400       {object}  models.ErrorResponse
+// @Failure      404       {object}  models.ErrorResponse
+// @Failure      500       {object}  models.ErrorResponse
+// @Router       /concordance [get]
+func (h *HadithHandler) GetKeywordInContext(c *gin.Context) {
+	query := kwic.Compile(c.Query("term"))
+	if query == nil {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Missing term",
+			Error:   "Query parameter term is required",
+		})
+		return
+	}
+
+	window, err := strconv.Atoi(c.DefaultQuery("window", "5"))
+	if err != nil || window < 0 || window > maxWindow {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid window",
+			Error:   "window must be an integer between 0 and 20",
+		})
+		return
+	}
+
+	order := c.Query("sort")
+	if order != kwic.SortNone && order != kwic.SortLeft && order != kwic.SortRight {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid sort",
+			Error:   "sort must be left or right",
+		})
+		return
+	}
+
+	field := c.Query("field")
+	if field != "" && field != "arab" && field != "id" {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid field",
+			Error:   "field must be arab or id",
+		})
+		return
+	}
+
+	// Parse query parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	// Set default pagination values
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	var narrators []string
+	if narrator := c.Query("narrator"); narrator != "" {
+		resolved, err := h.repoFor(c).ResolveNarrator(narrator)
+		if err != nil {
+			respond(c, http.StatusNotFound, models.ErrorResponse{
+				Status:  "error",
+				Message: "Narrator not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+		narrators = []string{resolved}
+	} else {
+		var err error
+		narrators, err = h.repoFor(c).GetAvailableNarrators()
+		if err != nil {
+			respond(c, http.StatusInternalServerError, models.ErrorResponse{
+				Status:  "error",
+				Message: "Failed to get narrators",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	var matches []kwic.Match
+	for _, narrator := range narrators {
+		hadiths, _, err := h.repoFor(c).GetHadithsByNarrator(narrator, models.QueryParams{})
+		if err != nil {
+			continue
+		}
+
+		for _, hadith := range hadiths {
+			texts := []struct{ field, text string }{{"arab", hadith.Arab}, {"id", hadith.ID}}
+			for _, t := range texts {
+				if field != "" && field != t.field {
+					continue
+				}
+				for _, match := range query.Find(t.text, window) {
+					match.Slug = narrator
+					match.Number = hadith.Number
+					match.Field = t.field
+					matches = append(matches, match)
+				}
+			}
+		}
+	}
+	kwic.SortMatches(matches, order)
+
+	// Apply pagination
+	totalItems := len(matches)
+	startIndex := (page - 1) * limit
+	endIndex := startIndex + limit
+
+	lines := []models.KWICLine{}
+	if startIndex < totalItems {
+		if endIndex > totalItems {
+			endIndex = totalItems
+		}
+		for _, match := range matches[startIndex:endIndex] {
+			lines = append(lines, match.KWICLine)
+		}
+	}
+
+	// Calculate pagination values
+	totalPages := (totalItems + limit - 1) / limit // Ceiling division
+
+	respond(c, http.StatusOK, models.PaginatedResponse{
+		Status:  "success",
+		Message: "Concordance retrieved successfully",
+		Data:    lines,
+		Pagination: models.Pagination{
+			CurrentPage: page,
+			TotalItems:  totalItems,
+			TotalPages:  totalPages,
+			PerPage:     limit,
+		},
+	})
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/ngram"
+)
+
+// GetNGrams godoc
+// @Summary      N-gram and collocation analysis
+// @Description  Returns the most frequent n-grams of 2 to 5 normalized words and the most significant two-word collocations, for a collection or the whole corpus
+// @Tags         analysis
+// @Produce      json,xml,plain,application/msgpack
+// @Param        narrator   query     string  false "Only analyze this narrator's collection (default: the whole corpus)"
+// @Param        field      query     string  false "Text to analyze: arab or id (default: arab)"
+// @Param        min_n      query     int     false "Shortest n-gram length (default: 2)"
+// @Param        max_n      query     int     false "Longest n-gram length (default: 5)"
+// @Param        min_count  query     int     false "Ignore n-grams occurring fewer times (default: 5)"
+// @Param        top        query     int     false "Number of n-grams and collocations to return (default: 50, max: 1000)"
+// @Param        measure    query     string  false "Collocation measure: pmi or llr (default: pmi)"
+// @Success      200        {object}  models.HadithResponse{data=models.NGramReport}
+// @Failure      400        {object}  models.ErrorResponse
+// @Failure      404        {object}  models.ErrorResponse
+// @Failure      500        {object}  models.ErrorResponse
+// @Router       /ngrams [get]
+func (h *HadithHandler) GetNGrams(c *gin.Context) {
+	opts := ngram.DefaultOptions
+	opts.Field = c.DefaultQuery("field", opts.Field)
+	opts.Measure = c.DefaultQuery("measure", opts.Measure)
+
+	var err error
+	for param, value := range map[string]*int{
+		"min_n":     &opts.MinN,
+		"max_n":     &opts.MaxN,
+		"min_count": &opts.MinCount,
+		"top":       &opts.Top,
+	} {
+		if raw := c.Query(param); raw != "" {
+			if *value, err = strconv.Atoi(raw); err != nil {
+				respond(c, http.StatusBadRequest, models.ErrorResponse{
+					Status:  "error",
+					Message: "Invalid parameter",
+					Error:   param + " must be an integer",
+				})
+				return
+			}
+		}
+	}
+
+	if err := opts.Validate(); err != nil {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var narrators []string
+	narrator := c.Query("narrator")
+	if narrator != "" {
+		resolved, err := h.repoFor(c).ResolveNarrator(narrator)
+		if err != nil {
+			respond(c, http.StatusNotFound, models.ErrorResponse{
+				Status:  "error",
+				Message: "Narrator not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+		narrator = resolved
+		narrators = []string{resolved}
+	} else {
+		narrators, err = h.repoFor(c).GetAvailableNarrators()
+		if err != nil {
+			respond(c, http.StatusInternalServerError, models.ErrorResponse{
+				Status:  "error",
+				Message: "Failed to get narrators",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	var docs [][]string
+	for _, narrator := range narrators {
+		hadiths, _, err := h.repoFor(c).GetHadithsByNarrator(narrator, models.QueryParams{})
+		if err != nil {
+			continue
+		}
+		for _, hadith := range hadiths {
+			docs = append(docs, ngram.Tokens(hadith, opts.Field))
+		}
+	}
+
+	report := ngram.Analyze(docs, opts)
+	report.Narrator = narrator
+
+	respond(c, http.StatusOK, models.HadithResponse{
+		Status:  "success",
+		Message: "N-grams retrieved successfully",
+		Data:    report,
+	})
+}
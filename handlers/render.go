@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+	"github.com/hadith-api/format"
+	"github.com/hadith-api/models"
+)
+
+// formats maps the values of the ?format= override onto their MIME types
+var formats = map[string]string{
+	"json":    binding.MIMEJSON,
+	"xml":     binding.MIMEXML,
+	"msgpack": binding.MIMEMSGPACK2,
+	"text":    binding.MIMEPlain,
+}
+
+// offered lists the MIME types responses can be rendered in, the first being the default
+var offered = []string{
+	binding.MIMEJSON,
+	binding.MIMEXML,
+	binding.MIMEXML2,
+	binding.MIMEMSGPACK,
+	binding.MIMEMSGPACK2,
+	binding.MIMEPlain,
+}
+
+// respond writes obj in the format requested with ?format= or negotiated from
+// the Accept header. An unknown ?format= and requests accepting none of the
+// formats get 406 Not Acceptable.
+func respond(c *gin.Context, code int, obj interface{}) {
+	name := c.Query("format")
+	if name == "" {
+		respondNegotiated(c, code, obj)
+		return
+	}
+
+	mime, ok := formats[name]
+	if !ok {
+		c.JSON(http.StatusNotAcceptable, models.ErrorResponse{
+			Status:  "error",
+			Message: "Not acceptable",
+			Error:   fmt.Sprintf("Unknown format %q, supported formats are json, xml, msgpack and text", name),
+		})
+		return
+	}
+	writeResponse(c, code, obj, mime)
+}
+
+// respondNegotiated writes obj in the format negotiated from the Accept
+// header only, for endpoints whose format parameter selects something else,
+// such as the file format of an export
+func respondNegotiated(c *gin.Context, code int, obj interface{}) {
+	mime := negotiate(c.GetHeader("Accept"))
+	if mime == "" {
+		c.JSON(http.StatusNotAcceptable, models.ErrorResponse{
+			Status:  "error",
+			Message: "Not acceptable",
+			Error:   "Supported formats are application/json, application/xml, application/msgpack and text/plain",
+		})
+		return
+	}
+	writeResponse(c, code, obj, mime)
+}
+
+// negotiate picks the offered MIME type the Accept header weighs highest,
+// honouring q-values, which gin's NegotiateFormat ignores. JSON wins ties,
+// and is also returned when none of the types the client prefers most can be
+// served but JSON is acceptable, so that a browser asking for HTML before
+// */* gets JSON rather than the XML it lists next. It returns an empty string
+// when no offered type is acceptable.
+func negotiate(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return offered[0]
+	}
+
+	type mediaRange struct {
+		typ, subtype string
+		q            float64
+	}
+	var ranges []mediaRange
+	top := 0.0
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		typ, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(params[0])), "/")
+		if !ok {
+			continue
+		}
+		q := 1.0
+		for _, param := range params[1:] {
+			key, value, _ := strings.Cut(param, "=")
+			if strings.TrimSpace(key) == "q" {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		ranges = append(ranges, mediaRange{typ, subtype, q})
+		if q > top {
+			top = q
+		}
+	}
+
+	// quality returns the q-value of the most specific range matching mime
+	quality := func(mime string) float64 {
+		typ, subtype, _ := strings.Cut(mime, "/")
+		q, specificity := 0.0, -1
+		for _, r := range ranges {
+			s := -1
+			switch {
+			case r.typ == typ && r.subtype == subtype:
+				s = 2
+			case r.typ == typ && r.subtype == "*":
+				s = 1
+			case r.typ == "*" && r.subtype == "*":
+				s = 0
+			}
+			if s > specificity {
+				q, specificity = r.q, s
+			}
+		}
+		return q
+	}
+
+	best, bestQ := "", 0.0
+	for _, mime := range offered {
+		if q := quality(mime); q > bestQ {
+			best, bestQ = mime, q
+		}
+	}
+	if best != "" && bestQ < top && quality(offered[0]) > 0 {
+		return offered[0]
+	}
+	return best
+}
+
+// writeResponse writes obj in the format of the given MIME type
+func writeResponse(c *gin.Context, code int, obj interface{}, mime string) {
+	if mime == binding.MIMEJSON {
+		c.JSON(code, obj)
+		return
+	}
+
+	value, err := format.Decode(obj)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to render response",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var body bytes.Buffer
+	switch mime {
+	case binding.MIMEMSGPACK, binding.MIMEMSGPACK2:
+		c.Render(code, render.MsgPack{Data: format.Plain(value)})
+		return
+	case binding.MIMEPlain:
+		err = format.WriteText(&body, value)
+	default:
+		err = format.WriteXML(&body, "response", value)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to render response",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Data(code, mime+"; charset=utf-8", body.Bytes())
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/repository"
+)
+
+const browserAccept = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{browserAccept, "application/json"},
+		{"application/xml", "application/xml"},
+		{"application/*", "application/json"},
+		// JSON wins ties
+		{"application/xml, application/json", "application/json"},
+		{"text/plain;q=0.5, application/json;q=0.4", "text/plain"},
+		{"Application/MsgPack", "application/msgpack"},
+		{"text/*", "text/xml"},
+		{"text/plain, text/*;q=0.5", "text/plain"},
+		{"application/json;q=0, */*", "application/xml"},
+		{"image/png", ""},
+		{"text/html", ""},
+		{"application/json;q=0", ""},
+	}
+
+	for _, tt := range tests {
+		if got := negotiate(tt.accept); got != tt.want {
+			t.Errorf("negotiate(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestRespondFormats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewHadithHandler(repository.NewFileRepository("../repository/testdata/data"))
+	router.GET("/narrators", handler.GetNarrators)
+
+	tests := []struct {
+		query  string
+		accept string
+		code   int
+		mime   string
+		body   string
+	}{
+		{"", "", http.StatusOK, "application/json", `"available":["alpha","beta"]`},
+		{"", browserAccept, http.StatusOK, "application/json", `"available":["alpha","beta"]`},
+		{"", "application/xml", http.StatusOK, "application/xml", "<available><item>alpha</item>"},
+		{"", "application/msgpack", http.StatusOK, "application/msgpack", "alpha"},
+		{"", "text/plain", http.StatusOK, "text/plain", "alpha"},
+		// ?format= overrides the Accept header
+		{"?format=xml", "application/json", http.StatusOK, "application/xml", "<available><item>alpha</item>"},
+		{"?format=msgpack", browserAccept, http.StatusOK, "application/msgpack", "alpha"},
+		{"?format=text", "", http.StatusOK, "text/plain", "alpha"},
+		{"?format=json", "application/xml", http.StatusOK, "application/json", `"available":["alpha","beta"]`},
+		{"", "image/png", http.StatusNotAcceptable, "application/json", "Supported formats"},
+		{"?format=yaml", "", http.StatusNotAcceptable, "application/json", "Unknown format"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/narrators"+tt.query, nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.code {
+			t.Errorf("GET %s (Accept %q) code = %d, want %d", tt.query, tt.accept, w.Code, tt.code)
+		}
+		if mime := w.Header().Get("Content-Type"); !strings.HasPrefix(mime, tt.mime) {
+			t.Errorf("GET %s (Accept %q) Content-Type = %q, want %s", tt.query, tt.accept, mime, tt.mime)
+		}
+		if !strings.Contains(w.Body.String(), tt.body) {
+			t.Errorf("GET %s (Accept %q) body = %q, want it to contain %q", tt.query, tt.accept, w.Body.String(), tt.body)
+		}
+	}
+}
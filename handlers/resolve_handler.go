@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/citation"
+	"github.com/hadith-api/models"
+)
+
+const (
+	// minMatchScore is the score a candidate needs to be reported as the match
+	minMatchScore = 0.9
+	// maxCandidates caps the number of candidates returned
+	maxCandidates = 10
+)
+
+// ResolveCitation godoc
+// @Summary      Resolve a free-text citation
+// @Description  Parses a citation such as "HR. Malik no. 12", "Muwatta 1/23" or "رواه الدارمي ٢٩٤٩" and returns the matching hadith or a ranked list of candidates
+// @Tags         hadiths
+// @Produce      json,xml,plain,application/msgpack
+// @Param        ref  query     string  true  "Citation text in Indonesian, English or Arabic"
+// @Success      200  {object}  models.HadithResponse{data=models.ResolvedCitation}
+// @Failure      400  {object}  models.ErrorResponse
+// @Failure      404  {object}  models.ErrorResponse
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /resolve [get]
+func (h *HadithHandler) ResolveCitation(c *gin.Context) {
+	text := c.Query("ref")
+	if text == "" {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Missing citation",
+			Error:   "The ref query parameter is required",
+		})
+		return
+	}
+
+	ref, err := citation.Parse(text)
+	if err != nil {
+		respond(c, http.StatusBadRequest, models.ErrorResponse{
+			Status:  "error",
+			Message: "Invalid citation",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	narrators, err := h.repoFor(c).GetAvailableNarrators()
+	if err != nil {
+		respond(c, http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to get narrators",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	aliases, err := h.repoFor(c).GetAliases()
+	if err != nil {
+		respond(c, http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to get narrator aliases",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	candidates := []models.CitationCandidate{}
+	for _, match := range citation.MatchCollections(ref.Collection, narrators, aliases) {
+		// A "book/number" citation counts within the book, not the collection
+		var hadith *models.Hadith
+		if ref.Book > 0 {
+			hadith, err = h.repoFor(c).GetHadithByBook(match.Slug, ref.Book, ref.Number)
+		} else {
+			hadith, err = h.repoFor(c).GetHadithByNumber(match.Slug, ref.Number)
+		}
+		if err != nil {
+			continue
+		}
+
+		candidates = append(candidates, models.CitationCandidate{
+			Slug:   match.Slug,
+			Number: hadith.Number,
+			Score:  math.Round(match.Score*100) / 100,
+			Hadith: hadith,
+		})
+	}
+
+	if len(candidates) == 0 {
+		number := ref.Number.String()
+		if ref.Book > 0 {
+			number = strconv.Itoa(ref.Book) + "/" + number
+		}
+		respond(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "No hadith matches the citation",
+			Error:   "No collection contains hadith number " + number + " under the cited name",
+		})
+		return
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	if len(candidates) > maxCandidates {
+		candidates = candidates[:maxCandidates]
+	}
+
+	result := models.ResolvedCitation{
+		Query:      text,
+		Collection: ref.Collection,
+		Number:     ref.Number,
+		Book:       ref.Book,
+		Candidates: candidates,
+	}
+
+	// Only report a match when the best candidate is both strong and unambiguous
+	if candidates[0].Score >= minMatchScore &&
+		(len(candidates) == 1 || candidates[1].Score < candidates[0].Score) {
+		result.Match = &candidates[0]
+	}
+
+	respond(c, http.StatusOK, models.HadithResponse{
+		Status:  "success",
+		Message: "Citation resolved successfully",
+		Data:    result,
+	})
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/repository"
+)
+
+func TestResolveCitation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewHadithHandler(repository.NewFileRepository("../repository/testdata/data"))
+	router.GET("/resolve", handler.ResolveCitation)
+
+	tests := []struct {
+		ref   string
+		code  int
+		match string
+	}{
+		{"HR. Alpha no. 3", http.StatusOK, "alpha 3"},
+		// Book and number count within the Book headings of the collection
+		{"Alpha 1/2", http.StatusOK, "alpha 2"},
+		{"Alpha 1/2a", http.StatusOK, "alpha 2a"},
+		{"Alpha 2/1", http.StatusOK, "alpha 3"},
+		{"Alpha 2/3", http.StatusOK, "alpha 5"},
+		// Not the continuous number, which would be hadith 4
+		{"Alpha 2/4", http.StatusNotFound, ""},
+		{"Alpha 3/1", http.StatusNotFound, ""},
+		// Beta has no Book headings
+		{"Beta 1/2", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/resolve?ref="+url.QueryEscape(tt.ref), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.code {
+			t.Errorf("resolve %q code = %d, want %d", tt.ref, w.Code, tt.code)
+			continue
+		}
+		if tt.match == "" {
+			continue
+		}
+
+		var body struct {
+			Data models.ResolvedCitation `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Data.Match == nil {
+			t.Errorf("resolve %q has no match, want %s: %s", tt.ref, tt.match, w.Body.String())
+			continue
+		}
+		if got := body.Data.Match.Slug + " " + body.Data.Match.Number.String(); got != tt.match {
+			t.Errorf("resolve %q match = %s, want %s", tt.ref, got, tt.match)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/models"
+)
+
+// GetStats godoc
+// @Summary      Get corpus statistics
+// @Description  Returns hadith counts, number ranges and gaps, text lengths, vocabulary size and top words for the whole corpus and each collection
+// @Tags         stats
+// @Produce      json,xml,plain,application/msgpack
+// @Success      200  {object}  models.HadithResponse{data=models.Stats}
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /stats [get]
+func (h *HadithHandler) GetStats(c *gin.Context) {
+	stats, err := h.repoFor(c).GetStats()
+	if err != nil {
+		respond(c, http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to get statistics",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, models.HadithResponse{
+		Status:  "success",
+		Message: "Statistics retrieved successfully",
+		Data:    stats,
+	})
+}
+
+// GetCollectionStats godoc
+// @Summary      Get collection statistics
+// @Description  Returns the hadith count, number range and gaps, text lengths, vocabulary size and top words of a narrator's collection
+// @Tags         stats
+// @Produce      json,xml,plain,application/msgpack
+// @Param        slug  path      string  true  "Narrator slug (e.g., muslim, bukhari)"
+// @Success      200   {object}  models.HadithResponse{data=models.CollectionStats}
+// @Failure      404   {object}  models.ErrorResponse
+// @Router       /stats/{slug} [get]
+func (h *HadithHandler) GetCollectionStats(c *gin.Context) {
+	narrator := c.Param("slug")
+
+	stats, err := h.repoFor(c).GetCollectionStats(narrator)
+	if err != nil {
+		respond(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "Collection not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, models.HadithResponse{
+		Status:  "success",
+		Message: "Statistics retrieved successfully",
+		Data:    stats,
+	})
+}
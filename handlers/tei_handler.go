@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/tei"
+)
+
+// ExportTEI godoc
+// @Summary      Export a collection as TEI XML
+// @Description  Streams a narrator's collection, or a range of it, as a TEI XML document with the Arabic text, translation, numbering and book and chapter structure where present
+// @Tags         export
+// @Produce      xml
+// @Param        slug   path      string  true  "Narrator slug (e.g., muslim, bukhari)"
+// @Param        range  query     string  false "Range of hadith numbers to export (e.g., 1-40)"
+// @Success      200    {file}    file
+// @Failure      400    {object}  models.ErrorResponse
+// @Failure      404    {object}  models.ErrorResponse
+// @Router       /export/{slug}/tei [get]
+func (h *HadithHandler) ExportTEI(c *gin.Context) {
+	narrator := c.Param("slug")
+
+	collection, err := h.repoFor(c).GetCollectionInfo(narrator)
+	if err != nil {
+		respondNegotiated(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "Collection not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var hadiths []models.Hadith
+	filename := narrator
+	if numberRange := c.Query("range"); numberRange != "" {
+		from, to, err := parseRange(numberRange)
+		if err != nil {
+			respondNegotiated(c, http.StatusBadRequest, models.ErrorResponse{
+				Status:  "error",
+				Message: "Invalid range",
+				Error:   err.Error(),
+			})
+			return
+		}
+		hadiths, err = h.repoFor(c).GetHadithRange(narrator, from, to)
+		filename = fmt.Sprintf("%s-%s-%s", narrator, from, to)
+	} else {
+		hadiths, _, err = h.repoFor(c).GetHadithsByNarrator(narrator, models.QueryParams{})
+	}
+	if err != nil {
+		respondNegotiated(c, http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to get hadiths",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Type", "application/tei+xml; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.tei.xml"`, filename))
+	c.Status(http.StatusOK)
+
+	writer, err := tei.NewWriter(c.Writer, tei.Header{
+		Slug:   collection.Slug,
+		Title:  collection.Title,
+		Author: collection.Compiler,
+	})
+	if err != nil {
+		log.Printf("TEI export of %s stopped: %v", narrator, err)
+		return
+	}
+
+	for i, hadith := range hadiths {
+		if err := writer.WriteHadith(hadith); err != nil {
+			log.Printf("TEI export of %s stopped: %v", narrator, err)
+			return
+		}
+		if (i+1)%exportFlushInterval == 0 {
+			c.Writer.Flush()
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Printf("TEI export of %s stopped: %v", narrator, err)
+	}
+	c.Writer.Flush()
+}
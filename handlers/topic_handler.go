@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/models"
+)
+
+// GetTopics godoc
+// @Summary      Get the topic taxonomy
+// @Description  Returns all topics with the number of hadiths tagged with each
+// @Tags         topics
+// @Produce      json,xml,plain,application/msgpack
+// @Success      200  {object}  models.HadithResponse{data=[]models.Topic}
+// @Failure      500  {object}  models.ErrorResponse
+// @Router       /topics [get]
+func (h *HadithHandler) GetTopics(c *gin.Context) {
+	topics, err := h.repoFor(c).GetTopics()
+	if err != nil {
+		respond(c, http.StatusInternalServerError, models.ErrorResponse{
+			Status:  "error",
+			Message: "Failed to get topics",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, models.HadithResponse{
+		Status:  "success",
+		Message: "Topics retrieved successfully",
+		Data:    topics,
+	})
+}
+
+// GetHadithsByTopic godoc
+// @Summary      Get hadiths by topic
+// @Description  Returns the hadiths tagged with a topic across all narrators, with pagination
+// @Tags         topics
+// @Produce      json,xml,plain,application/msgpack
+// @Param        topic     path      string  true  "Topic slug (e.g., prayer, fasting)"
+// @Param        narrator  query     string  false "Only return hadiths from this narrator"
+// @Param        page      query     int     false "Page number for pagination (default: 1)"
+// @Param        limit     query     int     false "Items per page for pagination (default: 10)"
+// @Success      200       {object}  models.PaginatedResponse{data=[]models.SelectedHadith}
+// @Failure      404       {object}  models.ErrorResponse
+// @Failure      500       {object}  models.ErrorResponse
+// @Router       /topics/{topic}/hadis [get]
+func (h *HadithHandler) GetHadithsByTopic(c *gin.Context) {
+	topic := c.Param("topic")
+
+	// Parse query parameters
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	// Set default pagination values
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	if _, err := h.repoFor(c).GetTopic(topic); err != nil {
+		respond(c, http.StatusNotFound, models.ErrorResponse{
+			Status:  "error",
+			Message: "Topic not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var narrators []string
+	if narrator := c.Query("narrator"); narrator != "" {
+		resolved, err := h.repoFor(c).ResolveNarrator(narrator)
+		if err != nil {
+			respond(c, http.StatusNotFound, models.ErrorResponse{
+				Status:  "error",
+				Message: "Narrator not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+		narrators = []string{resolved}
+	} else {
+		var err error
+		narrators, err = h.repoFor(c).GetAvailableNarrators()
+		if err != nil {
+			respond(c, http.StatusInternalServerError, models.ErrorResponse{
+				Status:  "error",
+				Message: "Failed to get narrators",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	var tagged []models.SelectedHadith
+	for _, narrator := range narrators {
+		hadiths, _, err := h.repoFor(c).GetHadithsByNarrator(narrator, models.QueryParams{Topic: topic})
+		if err != nil {
+			continue
+		}
+		for i := range hadiths {
+			tagged = append(tagged, models.SelectedHadith{Slug: narrator, Hadith: &hadiths[i]})
+		}
+	}
+
+	// Apply pagination
+	totalItems := len(tagged)
+	startIndex := (page - 1) * limit
+	endIndex := startIndex + limit
+
+	if startIndex >= totalItems {
+		tagged = []models.SelectedHadith{}
+	} else {
+		if endIndex > totalItems {
+			endIndex = totalItems
+		}
+		tagged = tagged[startIndex:endIndex]
+	}
+
+	// Calculate pagination values
+	totalPages := (totalItems + limit - 1) / limit // Ceiling division
+
+	respond(c, http.StatusOK, models.PaginatedResponse{
+		Status:  "success",
+		Message: "Hadiths retrieved successfully",
+		Data:    tagged,
+		Pagination: models.Pagination{
+			CurrentPage: page,
+			TotalItems:  totalItems,
+			TotalPages:  totalPages,
+			PerPage:     limit,
+		},
+	})
+}
@@ -0,0 +1,142 @@
+// Package hijri converts between Gregorian and Hijri dates using the tabular
+// (arithmetical) Islamic calendar. The tabular calendar may differ by a day
+// from Umm al-Qura or local moon sighting, which callers can correct with an
+// adjustment in days.
+package hijri
+
+import (
+	"fmt"
+	"time"
+)
+
+// epoch is the Julian Day Number of 1 Muharram 1 AH (16 July 622 CE, Julian calendar)
+const epoch = 1948440
+
+// Hijri month numbers
+const (
+	Muharram     = 1
+	Safar        = 2
+	RabiulAwwal  = 3
+	RabiulAkhir  = 4
+	JumadilAwwal = 5
+	JumadilAkhir = 6
+	Rajab        = 7
+	Syaban       = 8
+	Ramadhan     = 9
+	Syawal       = 10
+	Dzulqadah    = 11
+	Dzulhijjah   = 12
+)
+
+// monthNames are the Indonesian transliterations of the Hijri month names
+var monthNames = [...]string{
+	"Muharram", "Safar", "Rabi'ul Awwal", "Rabi'ul Akhir", "Jumadil Awwal", "Jumadil Akhir",
+	"Rajab", "Sya'ban", "Ramadhan", "Syawal", "Dzulqa'dah", "Dzulhijjah",
+}
+
+// monthNamesArabic are the Arabic Hijri month names
+var monthNamesArabic = [...]string{
+	"محرم", "صفر", "ربيع الأول", "ربيع الآخر", "جمادى الأولى", "جمادى الآخرة",
+	"رجب", "شعبان", "رمضان", "شوال", "ذو القعدة", "ذو الحجة",
+}
+
+// Date is a date in the Hijri calendar
+type Date struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+// FromGregorian returns the Hijri date of the calendar date of t, shifted by adjust days
+func FromGregorian(t time.Time, adjust int) Date {
+	jd := julianDay(t.Year(), int(t.Month()), t.Day()) + adjust
+
+	year := (30*(jd-epoch) + 10646) / 10631
+	// Correct the year estimate at year boundaries
+	for jd >= firstDay(year+1, 1) {
+		year++
+	}
+	for jd < firstDay(year, 1) {
+		year--
+	}
+
+	month := (jd-firstDay(year, 1))*2/59 + 1
+	if month > 12 {
+		month = 12
+	}
+	// Correct for the 29/30-day rounding of the month estimate
+	for month > 1 && jd < firstDay(year, month) {
+		month--
+	}
+	for month < 12 && jd >= firstDay(year, month+1) {
+		month++
+	}
+	day := jd - firstDay(year, month) + 1
+
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// ToGregorian returns the Gregorian date of a Hijri date, shifted back by adjust days
+func ToGregorian(d Date, adjust int) time.Time {
+	jd := firstDay(d.Year, d.Month) + d.Day - 1 - adjust
+	return fromJulianDay(jd)
+}
+
+// MonthName returns the Indonesian transliteration of the month name
+func (d Date) MonthName() string {
+	if d.Month < 1 || d.Month > 12 {
+		return ""
+	}
+	return monthNames[d.Month-1]
+}
+
+// MonthNameArabic returns the Arabic month name
+func (d Date) MonthNameArabic() string {
+	if d.Month < 1 || d.Month > 12 {
+		return ""
+	}
+	return monthNamesArabic[d.Month-1]
+}
+
+// String formats the date as e.g. "1 Ramadhan 1445 H"
+func (d Date) String() string {
+	return fmt.Sprintf("%d %s %d H", d.Day, d.MonthName(), d.Year)
+}
+
+// DaysInMonth returns the length of a Hijri month in the tabular calendar
+func DaysInMonth(year, month int) int {
+	if month == 12 {
+		return firstDay(year+1, 1) - firstDay(year, 12)
+	}
+	return firstDay(year, month+1) - firstDay(year, month)
+}
+
+// firstDay returns the Julian Day Number of the first day of a Hijri month.
+// Odd months have 30 days, even months 29, and Dzulhijjah gains a day in the
+// 11 leap years of each 30-year cycle.
+func firstDay(year, month int) int {
+	return (59*(month-1)+1)/2 + (year-1)*354 + (3+11*year)/30 + epoch
+}
+
+// julianDay returns the Julian Day Number of a Gregorian date
+func julianDay(year, month, day int) int {
+	a := (14 - month) / 12
+	y := year + 4800 - a
+	m := month + 12*a - 3
+	return day + (153*m+2)/5 + 365*y + y/4 - y/100 + y/400 - 32045
+}
+
+// fromJulianDay returns the Gregorian date of a Julian Day Number
+func fromJulianDay(jd int) time.Time {
+	a := jd + 32044
+	b := (4*a + 3) / 146097
+	c := a - 146097*b/4
+	d := (4*c + 3) / 1461
+	e := c - 1461*d/4
+	m := (5*e + 2) / 153
+
+	day := e - (153*m+2)/5 + 1
+	month := m + 3 - 12*(m/10)
+	year := 100*b + d - 4800 + m/10
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,86 @@
+package hijri
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFromGregorian(t *testing.T) {
+	tests := []struct {
+		date   string
+		adjust int
+		want   Date
+	}{
+		{"0622-07-19", 0, Date{1, Muharram, 1}},
+		{"2000-01-01", 0, Date{1420, Ramadhan, 24}},
+		{"2023-03-23", 0, Date{1444, Ramadhan, 1}},
+		{"2023-07-19", 0, Date{1445, Muharram, 1}},
+		{"2023-07-18", 0, Date{1444, Dzulhijjah, 29}},
+		{"2024-04-10", 0, Date{1445, Syawal, 1}},
+		// Umm al-Qura put Arafah 1445 a day before the tabular calendar
+		{"2024-06-15", 0, Date{1445, Dzulhijjah, 8}},
+		{"2024-06-15", 1, Date{1445, Dzulhijjah, 9}},
+		{"2024-07-07", 0, Date{1445, Dzulhijjah, 30}},
+		{"2024-07-08", -1, Date{1445, Dzulhijjah, 30}},
+	}
+
+	for _, tt := range tests {
+		day, err := time.Parse("2006-01-02", tt.date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := FromGregorian(day, tt.adjust); got != tt.want {
+			t.Errorf("FromGregorian(%s, %d) = %v, want %v", tt.date, tt.adjust, got, tt.want)
+		}
+		if got := ToGregorian(tt.want, tt.adjust); !got.Equal(day) {
+			t.Errorf("ToGregorian(%v, %d) = %s, want %s", tt.want, tt.adjust, got.Format("2006-01-02"), tt.date)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	start := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	for day := start; day.Year() < 2060; day = day.AddDate(0, 0, 1) {
+		d := FromGregorian(day, 0)
+		if d.Day < 1 || d.Day > DaysInMonth(d.Year, d.Month) {
+			t.Fatalf("FromGregorian(%s) = %+v, outside its month", day.Format("2006-01-02"), d)
+		}
+		if got := ToGregorian(d, 0); !got.Equal(day) {
+			t.Fatalf("ToGregorian(FromGregorian(%s)) = %s", day.Format("2006-01-02"), got.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestDaysInMonth(t *testing.T) {
+	tests := []struct {
+		year, month int
+		want        int
+	}{
+		{1445, Muharram, 30},
+		{1445, Safar, 29},
+		{1445, Ramadhan, 30},
+		{1445, Syawal, 29},
+		// 1445 is a leap year of the 30-year cycle, 1444 is not
+		{1445, Dzulhijjah, 30},
+		{1444, Dzulhijjah, 29},
+	}
+
+	for _, tt := range tests {
+		if got := DaysInMonth(tt.year, tt.month); got != tt.want {
+			t.Errorf("DaysInMonth(%d, %d) = %d, want %d", tt.year, tt.month, got, tt.want)
+		}
+	}
+}
+
+func TestDateNames(t *testing.T) {
+	d := Date{1445, Ramadhan, 1}
+	if got := d.String(); got != "1 Ramadhan 1445 H" {
+		t.Errorf("String = %q", got)
+	}
+	if got := d.MonthNameArabic(); got != "رمضان" {
+		t.Errorf("MonthNameArabic = %q", got)
+	}
+	if got := (Date{1445, 13, 1}).MonthName(); got != "" {
+		t.Errorf("MonthName of month 13 = %q, want empty", got)
+	}
+}
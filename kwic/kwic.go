@@ -0,0 +1,102 @@
+// Package kwic finds the occurrences of a term in hadith texts and returns
+// them as keyword-in-context lines. Matching is done on normalized words, so
+// a term without diacritics matches the vocalized Arabic text, and attached
+// Arabic proclitics and the article are ignored.
+package kwic
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/normalize"
+)
+
+// Sort orders
+const (
+	SortNone  = ""
+	SortLeft  = "left"
+	SortRight = "right"
+)
+
+// Match is an occurrence of a term in a text, with up to the requested number
+// of words on either side as they appear in the original text. The caller
+// fills in the reference of the hadith the text belongs to.
+type Match struct {
+	models.KWICLine
+	// sort keys: the normalized left context from the keyword outward and the normalized right context
+	leftKey  string
+	rightKey string
+}
+
+// Query is a compiled search term
+type Query struct {
+	words  []string
+	prefix bool
+}
+
+// Compile normalizes a term of one or more words. A trailing "*" matches any
+// word starting with the last word of the term. It returns nil for an empty term.
+func Compile(term string) *Query {
+	words, prefix := normalize.Term(term)
+	if len(words) == 0 {
+		return nil
+	}
+	return &Query{words: words, prefix: prefix}
+}
+
+// Find returns every occurrence of the query in text with window words of context
+func (q *Query) Find(text string, window int) []Match {
+	words := strings.Fields(text)
+	normalized := make([]string, len(words))
+	for i, word := range words {
+		normalized[i] = strings.Join(normalize.Tokenize(word), "")
+	}
+	forms := normalize.TokenForms(normalized)
+
+	var matches []Match
+	for i := 0; i+len(q.words) <= len(words); i++ {
+		if !normalize.MatchForms(forms, i, q.words, q.prefix) {
+			continue
+		}
+
+		end := i + len(q.words)
+		start := i - window
+		if start < 0 {
+			start = 0
+		}
+		stop := end + window
+		if stop > len(words) {
+			stop = len(words)
+		}
+
+		// The left sort key reads outward from the keyword
+		leftKey := make([]string, 0, i-start)
+		for j := i - 1; j >= start; j-- {
+			leftKey = append(leftKey, normalized[j])
+		}
+
+		matches = append(matches, Match{
+			KWICLine: models.KWICLine{
+				Left:    strings.Join(words[start:i], " "),
+				Keyword: strings.Join(words[i:end], " "),
+				Right:   strings.Join(words[end:stop], " "),
+			},
+			leftKey:  strings.Join(leftKey, " "),
+			rightKey: strings.Join(normalized[end:stop], " "),
+		})
+	}
+
+	return matches
+}
+
+// SortMatches orders matches by their left or right context. The sort is
+// stable, so matches with equal context keep their corpus order.
+func SortMatches(matches []Match, order string) {
+	switch order {
+	case SortLeft:
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].leftKey < matches[j].leftKey })
+	case SortRight:
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].rightKey < matches[j].rightKey })
+	}
+}
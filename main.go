@@ -7,6 +7,8 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/cli"
+	"github.com/hadith-api/config"
 	_ "github.com/hadith-api/docs"
 	"github.com/hadith-api/handlers"
 	"github.com/hadith-api/repository"
@@ -28,14 +30,25 @@ import (
 // @BasePath /api/v1
 // @securityDefinitions.basic BasicAuth
 func main() {
+	// Run a command-line tool instead of the server when a command is given
+	if len(os.Args) > 1 {
+		if err := cli.Run(os.Args[1], os.Args[2:]); err != nil {
+			log.Fatalf("Command %s failed: %v", os.Args[1], err)
+		}
+		return
+	}
+
 	// Determine environment
 	environment := os.Getenv("GO_ENV")
 	if environment == "" {
 		environment = "development"
 	}
 
-	// Set up the repository with the data directory
-	repo := repository.NewFileRepository("./api/data")
+	// Set up the repository of the configured storage backend
+	repo, err := repository.Open(config.GetConfig(), "./api/data")
+	if err != nil {
+		log.Fatalf("Failed to open repository: %v", err)
+	}
 
 	// Initialize the handlers with the repository
 	hadithHandler := handlers.NewHadithHandler(repo)
@@ -0,0 +1,34 @@
+package models
+
+// ResolvedCitation is the result of resolving a free-text citation into hadiths
+type ResolvedCitation struct {
+	Query      string `json:"query"`
+	Collection string `json:"collection,omitempty"`
+	// Number is an integer, or a string such as "12a" when it has a letter suffix
+	Number     Number              `json:"number" swaggertype:"integer"`
+	Book       int                 `json:"book,omitempty"`
+	Match      *CitationCandidate  `json:"match,omitempty"`
+	Candidates []CitationCandidate `json:"candidates"`
+}
+
+// CitationCandidate is a hadith that may be the one a citation refers to
+type CitationCandidate struct {
+	Slug string `json:"slug"`
+	// Number is an integer, or a string such as "12a" when it has a letter suffix
+	Number Number  `json:"number" swaggertype:"integer"`
+	Score  float64 `json:"score"`
+	Hadith *Hadith `json:"hadith"`
+}
+
+// Citation is a formatted citation of a hadith in a given style. Text styles
+// hold a string, while CSL-JSON holds a structured item.
+type Citation struct {
+	Style    string      `json:"style"`
+	Citation interface{} `json:"citation"`
+}
+
+// CitedHadith is a hadith together with its citation
+type CitedHadith struct {
+	*Hadith
+	Citation *Citation `json:"citation,omitempty"`
+}
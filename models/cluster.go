@@ -0,0 +1,19 @@
+package models
+
+// Cluster is a thematic group of hadiths found by the clustering job
+type Cluster struct {
+	ID           int      `json:"id"`
+	TotalHadiths int      `json:"total_hadiths"`
+	Keywords     []string `json:"keywords"`
+	// Hadiths maps narrator slugs onto the numbers of the hadiths in the cluster
+	Hadiths map[string][]Number `json:"hadiths,omitempty" swaggerignore:"true"`
+}
+
+// ClusterSet is the persisted output of a clustering run
+type ClusterSet struct {
+	GeneratedAt string    `json:"generated_at"`
+	Field       string    `json:"field"`
+	K           int       `json:"k"`
+	Seed        int64     `json:"seed"`
+	Clusters    []Cluster `json:"clusters"`
+}
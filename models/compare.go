@@ -0,0 +1,25 @@
+package models
+
+// Comparison is a word-level diff of two hadiths
+type Comparison struct {
+	A           SelectedHadith `json:"a"`
+	B           SelectedHadith `json:"b"`
+	Diacritics  string         `json:"diacritics"`
+	Arabic      TextDiff       `json:"arab"`
+	Translation TextDiff       `json:"id"`
+}
+
+// TextDiff lists the operations turning one text into another. Similarity is
+// the share of words both texts have in common, from 0 to 1.
+type TextDiff struct {
+	Similarity float64  `json:"similarity"`
+	Ops        []DiffOp `json:"ops"`
+}
+
+// DiffOp is a run of words that are equal in both texts, only in A (delete)
+// or only in B (insert). Equal runs carry the words as written on each side.
+type DiffOp struct {
+	Op string `json:"op"`
+	A  string `json:"a,omitempty"`
+	B  string `json:"b,omitempty"`
+}
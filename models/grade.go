@@ -0,0 +1,76 @@
+package models
+
+import "strings"
+
+// Canonical grade categories used for filtering and summaries
+const (
+	GradeSahih    = "sahih"
+	GradeHasan    = "hasan"
+	GradeDaif     = "daif"
+	GradeMaudu    = "maudu"
+	GradeUngraded = "ungraded"
+)
+
+// Grade is an authenticity assessment of a hadith given by a single grader
+type Grade struct {
+	Grader string `json:"grader"`
+	Grade  string `json:"grade"`
+	Source string `json:"source,omitempty"`
+}
+
+// gradeAliases maps the common spellings of a grade onto its canonical category
+var gradeAliases = map[string]string{
+	"sahih":  GradeSahih,
+	"shahih": GradeSahih,
+	"saheeh": GradeSahih,
+	"shohih": GradeSahih,
+	"صحيح":   GradeSahih,
+	"hasan":  GradeHasan,
+	"hassan": GradeHasan,
+	"حسن":    GradeHasan,
+	"daif":   GradeDaif,
+	"dhaif":  GradeDaif,
+	"daeef":  GradeDaif,
+	"dhaeef": GradeDaif,
+	"dhoif":  GradeDaif,
+	"ضعيف":   GradeDaif,
+	"maudu":  GradeMaudu,
+	"maudhu": GradeMaudu,
+	"mawdu":  GradeMaudu,
+	"mawdoo": GradeMaudu,
+	"موضوع":  GradeMaudu,
+}
+
+// NormalizeGrade maps a free-form grade such as "Da'if" or "Hasan Sahih" onto
+// its canonical category. Compound grades take the category of their first
+// recognized word; unrecognized grades are returned lowercased.
+func NormalizeGrade(grade string) string {
+	cleaned := strings.ToLower(strings.TrimSpace(grade))
+	if cleaned == "" {
+		return GradeUngraded
+	}
+
+	cleaned = strings.NewReplacer("'", "", "`", "", "’", "", "‘", "", "-", " ").Replace(cleaned)
+	for _, word := range strings.Fields(cleaned) {
+		if canonical, ok := gradeAliases[word]; ok {
+			return canonical
+		}
+	}
+
+	return cleaned
+}
+
+// HasGrade reports whether any of the hadith's graders assigned the given grade
+func (h Hadith) HasGrade(grade string) bool {
+	target := NormalizeGrade(grade)
+	if target == GradeUngraded {
+		return len(h.Grades) == 0
+	}
+
+	for _, g := range h.Grades {
+		if NormalizeGrade(g.Grade) == target {
+			return true
+		}
+	}
+	return false
+}
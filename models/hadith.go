@@ -1,10 +1,20 @@
 package models
 
+import "encoding/json"
+
 // Hadith represents a single hadith with its number, Arabic text, and Indonesian translation
 type Hadith struct {
-	Number int    `json:"number"`
-	Arab   string `json:"arab"`
-	ID     string `json:"id"`
+	// Number is an integer, or a string such as "12a" when it has a letter suffix
+	Number Number  `json:"number" swaggertype:"integer"`
+	Arab   string  `json:"arab"`
+	ID     string  `json:"id"`
+	Grades []Grade `json:"grades,omitempty"`
+	Tags   []Tag   `json:"tags,omitempty"`
+	// Book and Chapter are the headings the hadith is found under, where the collection has them
+	Book    string `json:"book,omitempty"`
+	Chapter string `json:"chapter,omitempty"`
+	// Hash is the content hash, set as the hadith is loaded, see ContentHash
+	Hash string `json:"hash,omitempty"`
 }
 
 // HadithResponse is the standard response format for hadith API endpoints
@@ -42,9 +52,119 @@ type Narrators struct {
 	Available []string `json:"available"`
 }
 
+// Collection describes a single narrator's collection
+type Collection struct {
+	Slug         string         `json:"slug"`
+	Name         string         `json:"name,omitempty"`
+	Title        string         `json:"title,omitempty"`
+	Compiler     string         `json:"compiler,omitempty"`
+	TotalHadiths int            `json:"total_hadiths"`
+	Grades       map[string]int `json:"grades"`
+	Schemes      []string       `json:"schemes"`
+	Aliases      []string       `json:"aliases"`
+}
+
+// Concordance lists the numbers a hadith carries in each numbering scheme
+type Concordance struct {
+	Scheme     string              `json:"scheme"`
+	Number     Number              `json:"number"`
+	References map[string][]Number `json:"references"`
+}
+
 // QueryParams represents the possible query parameters for filtering hadiths
 type QueryParams struct {
 	Page  int
 	Limit int
 	Query string
-}
\ No newline at end of file
+	Grade string
+	Topic string
+}
+
+// HadithReference identifies a single hadith by narrator and number
+type HadithReference struct {
+	Slug string `json:"slug"`
+	// Number is an integer, or a string such as "12a" when it has a letter suffix
+	Number Number `json:"number" swaggertype:"integer"`
+	Scheme string `json:"scheme,omitempty"`
+}
+
+// BatchRequest is the request body for retrieving several hadiths at once
+type BatchRequest struct {
+	References []BatchReference `json:"references"`
+}
+
+// BatchReference is a reference in a batch request. Its number is kept
+// undecoded until the reference is looked up, so that an invalid number only
+// fails its own reference.
+type BatchReference struct {
+	Slug string `json:"slug"`
+	// Number is an integer, or a string such as "12a" for a number with a letter suffix
+	Number json.RawMessage `json:"number" swaggertype:"integer"`
+	Scheme string          `json:"scheme,omitempty"`
+}
+
+// Reference decodes the number of the reference. A missing number is left zero.
+func (r BatchReference) Reference() (HadithReference, error) {
+	ref := HadithReference{Slug: r.Slug, Scheme: r.Scheme}
+	if len(r.Number) == 0 || string(r.Number) == "null" {
+		return ref, nil
+	}
+	if err := json.Unmarshal(r.Number, &ref.Number); err != nil {
+		return ref, err
+	}
+	return ref, nil
+}
+
+// BatchResult is the outcome of looking up a single reference in a batch. The
+// number is omitted when it is missing or could not be parsed.
+type BatchResult struct {
+	Slug string `json:"slug"`
+	// Number is an integer, or a string such as "12a" when it has a letter suffix
+	Number *Number `json:"number,omitempty" swaggertype:"integer"`
+	Hadith *Hadith `json:"hadith,omitempty"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// BatchResponse holds the results of a batch request in request order
+type BatchResponse struct {
+	Found    int           `json:"found"`
+	NotFound int           `json:"not_found"`
+	Results  []BatchResult `json:"results"`
+}
+
+// SelectedHadith is a hadith picked from the corpus together with its narrator
+type SelectedHadith struct {
+	Slug   string  `json:"slug"`
+	Hadith *Hadith `json:"hadith"`
+}
+
+// DailyHadith is the hadith selected for a given day
+type DailyHadith struct {
+	Date     string    `json:"date"`
+	Timezone string    `json:"timezone"`
+	Hijri    HijriDate `json:"hijri"`
+	Occasion string    `json:"occasion,omitempty"`
+	Slug     string    `json:"slug"`
+	Hadith   *Hadith   `json:"hadith"`
+}
+
+// HijriDate is a date in the Hijri calendar
+type HijriDate struct {
+	Year            int    `json:"year"`
+	Month           int    `json:"month"`
+	Day             int    `json:"day"`
+	MonthName       string `json:"month_name"`
+	MonthNameArabic string `json:"month_name_arabic"`
+	Formatted       string `json:"formatted"`
+}
+
+// Occasion is a recurring period, such as Ramadan or Friday, with its own
+// selection of hadiths for the daily hadith. It applies when every rule that is
+// set matches: the Hijri month, the Hijri day range within it, and the weekday.
+type Occasion struct {
+	Name       string              `json:"name"`
+	HijriMonth int                 `json:"hijri_month,omitempty"`
+	HijriDays  []int               `json:"hijri_days,omitempty"`
+	Weekday    string              `json:"weekday,omitempty"`
+	Hadiths    map[string][]Number `json:"hadiths"`
+}
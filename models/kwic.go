@@ -0,0 +1,13 @@
+package models
+
+// KWICLine is an occurrence of a term shown with its surrounding words
+type KWICLine struct {
+	Slug string `json:"slug"`
+	// Number is an integer, or a string such as "12a" when it has a letter suffix
+	Number Number `json:"number" swaggertype:"integer"`
+	// Field is the text the term was found in: arab or id
+	Field   string `json:"field"`
+	Left    string `json:"left"`
+	Keyword string `json:"keyword"`
+	Right   string `json:"right"`
+}
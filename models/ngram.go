@@ -0,0 +1,29 @@
+package models
+
+// NGram is a sequence of normalized words with its number of occurrences
+type NGram struct {
+	Words string `json:"words"`
+	N     int    `json:"n"`
+	Count int    `json:"count"`
+}
+
+// Collocation is a pair of words occurring together more often than chance
+type Collocation struct {
+	Words         string  `json:"words"`
+	Count         int     `json:"count"`
+	PMI           float64 `json:"pmi"`
+	LogLikelihood float64 `json:"log_likelihood"`
+}
+
+// NGramReport lists the frequent n-grams and collocations of a collection or the whole corpus
+type NGramReport struct {
+	// Narrator is empty for the whole corpus
+	Narrator     string        `json:"narrator,omitempty"`
+	Field        string        `json:"field"`
+	MinN         int           `json:"min_n"`
+	MaxN         int           `json:"max_n"`
+	MinCount     int           `json:"min_count"`
+	Measure      string        `json:"measure"`
+	NGrams       []NGram       `json:"ngrams"`
+	Collocations []Collocation `json:"collocations"`
+}
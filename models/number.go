@@ -0,0 +1,79 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultScheme is the numbering scheme used by the collection data files
+const DefaultScheme = "default"
+
+// Number is a hadith reference number with an optional sub-letter, such as 12 or 12a.
+// It is encoded in JSON as a plain number when there is no sub-letter and as a string otherwise.
+type Number struct {
+	Value  int
+	Suffix string
+}
+
+// ParseNumber parses a hadith reference number such as "12" or "12a"
+func ParseNumber(s string) (Number, error) {
+	s = strings.TrimSpace(s)
+
+	digits := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) })
+	if digits == -1 {
+		digits = len(s)
+	}
+
+	value, err := strconv.Atoi(s[:digits])
+	if err != nil || value < 1 {
+		return Number{}, fmt.Errorf("invalid hadith number %q", s)
+	}
+
+	suffix := strings.ToLower(s[digits:])
+	for _, r := range suffix {
+		if r < 'a' || r > 'z' {
+			return Number{}, fmt.Errorf("invalid hadith number %q", s)
+		}
+	}
+
+	return Number{Value: value, Suffix: suffix}, nil
+}
+
+// String returns the number in its written form, e.g. "12a"
+func (n Number) String() string {
+	return strconv.Itoa(n.Value) + n.Suffix
+}
+
+// Less reports whether n is ordered before other
+func (n Number) Less(other Number) bool {
+	if n.Value != other.Value {
+		return n.Value < other.Value
+	}
+	return n.Suffix < other.Suffix
+}
+
+// MarshalJSON encodes the number as a JSON number, or as a string when it has a sub-letter
+func (n Number) MarshalJSON() ([]byte, error) {
+	if n.Suffix == "" {
+		return []byte(strconv.Itoa(n.Value)), nil
+	}
+	return json.Marshal(n.String())
+}
+
+// UnmarshalJSON accepts both JSON numbers and strings such as "12a"
+func (n *Number) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+
+	parsed, err := ParseNumber(s)
+	if err != nil {
+		return err
+	}
+	*n = parsed
+	return nil
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Number
+		wantErr bool
+	}{
+		{"12", Number{Value: 12}, false},
+		{" 12a ", Number{Value: 12, Suffix: "a"}, false},
+		{"12A", Number{Value: 12, Suffix: "a"}, false},
+		{"7bis", Number{Value: 7, Suffix: "bis"}, false},
+		{"", Number{}, true},
+		{"0", Number{}, true},
+		{"-3", Number{}, true},
+		{"a12", Number{}, true},
+		{"12-a", Number{}, true},
+		{"12 a", Number{}, true},
+		{"١٢", Number{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseNumber(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseNumber(%q) = %+v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseNumber(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestNumberJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`12`, `12`},
+		{`"12"`, `12`},
+		{`"12A"`, `"12a"`},
+	}
+
+	for _, tt := range tests {
+		var n Number
+		if err := json.Unmarshal([]byte(tt.in), &n); err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.in, err)
+			continue
+		}
+		if got, _ := json.Marshal(n); string(got) != tt.want {
+			t.Errorf("Marshal of %s = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	var n Number
+	if err := json.Unmarshal([]byte(`"x"`), &n); err == nil {
+		t.Error(`Unmarshal("x"): want an error`)
+	}
+}
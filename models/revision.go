@@ -0,0 +1,36 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// ContentHash returns a short hash of the hadith's content, including its
+// grades, tags and headings, that changes whenever any of them does
+func (h Hadith) ContentHash() string {
+	h.Hash = ""
+	data, _ := json.Marshal(h)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Revision is a numbered state of the dataset. Revisions are recorded as
+// snapshots of the content hashes. Pending is set when the data has changed
+// since the revision; the changes get the next number once they are recorded.
+type Revision struct {
+	Revision     int    `json:"revision"`
+	CreatedAt    string `json:"created_at,omitempty"`
+	Pending      bool   `json:"pending"`
+	TotalHadiths int    `json:"total_hadiths"`
+}
+
+// ChangeSet lists the hadiths added, modified and removed between a revision
+// and the current one. Added and modified hadiths carry their full content.
+type ChangeSet struct {
+	Since    int               `json:"since"`
+	Revision int               `json:"revision"`
+	Added    []SelectedHadith  `json:"added"`
+	Modified []SelectedHadith  `json:"modified"`
+	Removed  []HadithReference `json:"removed"`
+}
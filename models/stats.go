@@ -0,0 +1,55 @@
+package models
+
+// Stats summarizes the whole corpus and each of its collections
+type Stats struct {
+	TotalHadiths int               `json:"total_hadiths"`
+	Arabic       TextStats         `json:"arab"`
+	Translation  TextStats         `json:"id"`
+	Collections  []CollectionStats `json:"collections"`
+}
+
+// CollectionStats summarizes a single collection
+type CollectionStats struct {
+	Slug         string `json:"slug"`
+	TotalHadiths int    `json:"total_hadiths"`
+	// FirstNumber is an integer, or a string such as "12a" when it has a letter suffix
+	FirstNumber Number `json:"first_number" swaggertype:"integer"`
+	// LastNumber is an integer, or a string such as "12a" when it has a letter suffix
+	LastNumber Number `json:"last_number" swaggertype:"integer"`
+	// Gaps are the ranges of numbers between the first and last that no hadith has
+	Gaps           []NumberRange `json:"gaps"`
+	MissingNumbers int           `json:"missing_numbers"`
+	Arabic         TextStats     `json:"arab"`
+	Translation    TextStats     `json:"id"`
+}
+
+// NumberRange is an inclusive range of hadith numbers
+type NumberRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// TextStats describes the Arabic text or the translation of a set of hadiths.
+// Lengths are in characters, words are counted after normalization.
+type TextStats struct {
+	AverageLength  float64       `json:"average_length"`
+	AverageWords   float64       `json:"average_words"`
+	Longest        LongestHadith `json:"longest"`
+	VocabularySize int           `json:"vocabulary_size"`
+	// TopWords are the most frequent normalized words, excluding stopwords
+	TopWords []WordCount `json:"top_words"`
+}
+
+// LongestHadith identifies the hadith with the longest text
+type LongestHadith struct {
+	Slug string `json:"slug"`
+	// Number is an integer, or a string such as "12a" when it has a letter suffix
+	Number Number `json:"number" swaggertype:"integer"`
+	Length int    `json:"length"`
+}
+
+// WordCount is a word with its number of occurrences
+type WordCount struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Topic is an entry in the topic taxonomy
+type Topic struct {
+	Slug         string `json:"slug"`
+	Name         string `json:"name"`
+	NameEnglish  string `json:"name_en,omitempty"`
+	NameArabic   string `json:"name_ar,omitempty"`
+	TotalHadiths int    `json:"total_hadiths"`
+}
+
+// Tag attaches a topic to a hadith. Tags assigned automatically carry the
+// confidence of the assignment; hand-curated tags have none.
+type Tag struct {
+	Topic      string  `json:"topic"`
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// UnmarshalJSON accepts both a plain topic slug and a {"topic", "confidence"} object
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	var topic string
+	if err := json.Unmarshal(data, &topic); err == nil {
+		*t = Tag{Topic: topic}
+		return nil
+	}
+
+	type tag Tag
+	return json.Unmarshal(data, (*tag)(t))
+}
+
+// HasTopic reports whether the hadith is tagged with the given topic
+func (h Hadith) HasTopic(topic string) bool {
+	for _, t := range h.Tags {
+		if strings.EqualFold(t.Topic, topic) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+// Package ngram counts word n-grams in hadith texts and scores two-word
+// collocations, to find formulaic phrases and recurring matn fragments
+package ngram
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/normalize"
+)
+
+// Collocation measures
+const (
+	MeasurePMI           = "pmi"
+	MeasureLogLikelihood = "llr"
+)
+
+// Text fields that can be analyzed
+const (
+	FieldArabic      = "arab"
+	FieldTranslation = "id"
+)
+
+// Options selects the n-gram lengths, the cut-offs and the collocation measure
+type Options struct {
+	Field    string
+	MinN     int
+	MaxN     int
+	MinCount int
+	Top      int
+	Measure  string
+}
+
+// DefaultOptions are the options used when none are given
+var DefaultOptions = Options{
+	Field:    FieldArabic,
+	MinN:     2,
+	MaxN:     5,
+	MinCount: 5,
+	Top:      50,
+	Measure:  MeasurePMI,
+}
+
+// MaxLength is the longest n-gram that can be counted
+const MaxLength = 5
+
+// Validate checks that the options are within the supported ranges
+func (o Options) Validate() error {
+	switch {
+	case o.Field != FieldArabic && o.Field != FieldTranslation:
+		return fmt.Errorf("field must be %s or %s", FieldArabic, FieldTranslation)
+	case o.MinN < 2 || o.MaxN > MaxLength || o.MinN > o.MaxN:
+		return fmt.Errorf("n-gram lengths must be between 2 and %d, with min_n not above max_n", MaxLength)
+	case o.MinCount < 1:
+		return fmt.Errorf("min_count must be at least 1")
+	case o.Top < 1 || o.Top > 1000:
+		return fmt.Errorf("top must be between 1 and 1000")
+	case o.Measure != MeasurePMI && o.Measure != MeasureLogLikelihood:
+		return fmt.Errorf("measure must be %s or %s", MeasurePMI, MeasureLogLikelihood)
+	}
+	return nil
+}
+
+// Tokens returns the normalized words of a hadith's Arabic text or translation
+func Tokens(h models.Hadith, field string) []string {
+	if field == FieldTranslation {
+		return normalize.Tokenize(h.ID)
+	}
+	return normalize.Tokenize(h.Arab)
+}
+
+// Analyze counts the n-grams of the tokenized texts and reports the most
+// frequent n-grams and the highest scoring collocations
+func Analyze(docs [][]string, opts Options) *models.NGramReport {
+	counter := NewCounter(docs, opts.MaxN, opts.MinCount)
+
+	return &models.NGramReport{
+		Field:        opts.Field,
+		MinN:         opts.MinN,
+		MaxN:         opts.MaxN,
+		MinCount:     opts.MinCount,
+		Measure:      opts.Measure,
+		NGrams:       counter.Top(opts.MinN, opts.MaxN, opts.Top),
+		Collocations: counter.Collocations(opts.Measure, opts.Top),
+	}
+}
+
+// Counter counts n-grams of up to a maximum length over a set of texts
+type Counter struct {
+	maxN     int
+	minCount int
+	// counts[n] holds the counts of the n-grams of n words, keyed by the words joined with spaces
+	counts []map[string]int
+	// totals[n] is the number of n-gram positions of n words
+	totals []int
+	// first and second count the words in the first and in the second
+	// position of the two-word n-grams, the marginals of the collocations
+	first, second map[string]int
+}
+
+// NewCounter counts the n-grams of 1 to maxN words in the given tokenized
+// texts. N-grams never span two texts. Only n-grams occurring at least
+// minCount times are kept; as an n-gram cannot occur more often than the
+// shorter n-grams it contains, longer n-grams are only counted when both
+// their prefix and suffix were kept.
+func NewCounter(docs [][]string, maxN, minCount int) *Counter {
+	if minCount < 1 {
+		minCount = 1
+	}
+
+	c := &Counter{
+		maxN:     maxN,
+		minCount: minCount,
+		counts:   make([]map[string]int, maxN+1),
+		totals:   make([]int, maxN+1),
+		first:    make(map[string]int),
+		second:   make(map[string]int),
+	}
+
+	if maxN >= 2 {
+		for _, tokens := range docs {
+			for i := 0; i+1 < len(tokens); i++ {
+				c.first[tokens[i]]++
+				c.second[tokens[i+1]]++
+			}
+		}
+	}
+
+	for n := 1; n <= maxN; n++ {
+		counts := make(map[string]int)
+		for _, tokens := range docs {
+			for i := 0; i+n <= len(tokens); i++ {
+				c.totals[n]++
+				if n > 1 && (!c.frequent(tokens[i:i+n-1]) || !c.frequent(tokens[i+1:i+n])) {
+					continue
+				}
+				counts[strings.Join(tokens[i:i+n], " ")]++
+			}
+		}
+
+		// Prune rare n-grams so that the next length can rely on them
+		for gram, count := range counts {
+			if count < minCount {
+				delete(counts, gram)
+			}
+		}
+		c.counts[n] = counts
+	}
+
+	return c
+}
+
+// frequent reports whether an n-gram was kept
+func (c *Counter) frequent(words []string) bool {
+	return c.counts[len(words)][strings.Join(words, " ")] >= c.minCount
+}
+
+// Top returns the most frequent n-grams of minN to maxN words, most frequent first
+func (c *Counter) Top(minN, maxN, limit int) []models.NGram {
+	if maxN > c.maxN {
+		maxN = c.maxN
+	}
+
+	grams := []models.NGram{}
+	for n := minN; n <= maxN; n++ {
+		for gram, count := range c.counts[n] {
+			grams = append(grams, models.NGram{Words: gram, N: n, Count: count})
+		}
+	}
+
+	sort.Slice(grams, func(i, j int) bool {
+		if grams[i].Count != grams[j].Count {
+			return grams[i].Count > grams[j].Count
+		}
+		if grams[i].N != grams[j].N {
+			return grams[i].N > grams[j].N
+		}
+		return grams[i].Words < grams[j].Words
+	})
+	if len(grams) > limit {
+		grams = grams[:limit]
+	}
+
+	return grams
+}
+
+// Collocations scores the kept two-word n-grams by pointwise mutual
+// information or Dunning's log-likelihood ratio and returns the highest scoring
+func (c *Counter) Collocations(measure string, limit int) []models.Collocation {
+	if c.maxN < 2 {
+		return []models.Collocation{}
+	}
+
+	// The words are counted over the bigram positions, as the first and as
+	// the second word, so that the contingency table adds up to the total
+	total := float64(c.totals[2])
+	collocations := []models.Collocation{}
+	for gram, count := range c.counts[2] {
+		words := strings.SplitN(gram, " ", 2)
+		first, second := float64(c.first[words[0]]), float64(c.second[words[1]])
+		both := float64(count)
+
+		collocations = append(collocations, models.Collocation{
+			Words:         gram,
+			Count:         count,
+			PMI:           round(math.Log2(both * total / (first * second))),
+			LogLikelihood: round(logLikelihood(both, first-both, second-both, total-first-second+both)),
+		})
+	}
+
+	score := func(col models.Collocation) float64 {
+		if measure == MeasureLogLikelihood {
+			return col.LogLikelihood
+		}
+		return col.PMI
+	}
+	sort.Slice(collocations, func(i, j int) bool {
+		if score(collocations[i]) != score(collocations[j]) {
+			return score(collocations[i]) > score(collocations[j])
+		}
+		return collocations[i].Words < collocations[j].Words
+	})
+	if len(collocations) > limit {
+		collocations = collocations[:limit]
+	}
+
+	return collocations
+}
+
+// logLikelihood is Dunning's G² statistic for a 2x2 contingency table of
+// the bigram count, each word without the other, and neither word
+func logLikelihood(k11, k12, k21, k22 float64) float64 {
+	total := k11 + k12 + k21 + k22
+	rows := []float64{k11 + k12, k21 + k22}
+	cols := []float64{k11 + k21, k12 + k22}
+	cells := [][]float64{{k11, k12}, {k21, k22}}
+
+	g := 0.0
+	for i := range cells {
+		for j, observed := range cells[i] {
+			if observed <= 0 {
+				continue
+			}
+			expected := rows[i] * cols[j] / total
+			g += observed * math.Log(observed/expected)
+		}
+	}
+	return 2 * g
+}
+
+// round rounds a score to three decimal places
+func round(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
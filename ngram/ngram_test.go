@@ -0,0 +1,50 @@
+package ngram
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hadith-api/models"
+)
+
+func TestCollocations(t *testing.T) {
+	// The bigrams are "a b" twice, "b c" and "c a". "a" is the first word of
+	// two of them although it occurs three times, and "c" ends only one.
+	docs := [][]string{{"a", "b", "c"}, {"a", "b"}, {"c", "a"}}
+
+	tests := []struct {
+		measure string
+		want    []models.Collocation
+	}{
+		{MeasurePMI, []models.Collocation{
+			{Words: "b c", Count: 1, PMI: 2, LogLikelihood: 4.499},
+			{Words: "c a", Count: 1, PMI: 2, LogLikelihood: 4.499},
+			{Words: "a b", Count: 2, PMI: 1, LogLikelihood: 5.545},
+		}},
+		{MeasureLogLikelihood, []models.Collocation{
+			{Words: "a b", Count: 2, PMI: 1, LogLikelihood: 5.545},
+			{Words: "b c", Count: 1, PMI: 2, LogLikelihood: 4.499},
+			{Words: "c a", Count: 1, PMI: 2, LogLikelihood: 4.499},
+		}},
+	}
+
+	for _, tt := range tests {
+		got := NewCounter(docs, 2, 1).Collocations(tt.measure, 10)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.measure, got, tt.want)
+		}
+	}
+}
+
+func TestCollocationsLimitAndMinCount(t *testing.T) {
+	docs := [][]string{{"a", "b", "c"}, {"a", "b"}, {"c", "a"}}
+
+	got := NewCounter(docs, 2, 2).Collocations(MeasurePMI, 10)
+	if len(got) != 1 || got[0].Words != "a b" {
+		t.Errorf("min count 2: got %+v, want only a b", got)
+	}
+
+	if got := NewCounter(docs, 2, 1).Collocations(MeasurePMI, 1); len(got) != 1 {
+		t.Errorf("limit 1: got %d collocations", len(got))
+	}
+}
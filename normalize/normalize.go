@@ -0,0 +1,146 @@
+// Package normalize provides text normalization shared by search, citation
+// parsing and corpus analysis
+package normalize
+
+import (
+	"strings"
+	"unicode"
+)
+
+// arabicLetters folds letter variants that are commonly written interchangeably
+var arabicLetters = strings.NewReplacer(
+	"أ", "ا",
+	"إ", "ا",
+	"آ", "ا",
+	"ٱ", "ا",
+	"ى", "ي",
+	"ة", "ه",
+	"ؤ", "و",
+	"ئ", "ي",
+	"ـ", "", // tatweel
+)
+
+// IsDiacritic reports whether r is an Arabic diacritic (harakat, tanwin, shadda, sukun, etc.)
+func IsDiacritic(r rune) bool {
+	return (r >= 0x064B && r <= 0x065F) || r == 0x0670 || (r >= 0x06D6 && r <= 0x06ED)
+}
+
+// StripDiacritics removes Arabic diacritics, keeping the letters
+func StripDiacritics(s string) string {
+	return strings.Map(func(r rune) rune {
+		if IsDiacritic(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// Arabic strips diacritics and tatweel and folds alef, ya, ta marbuta and hamza carrier variants
+func Arabic(s string) string {
+	return arabicLetters.Replace(StripDiacritics(s))
+}
+
+// Digits converts Arabic-Indic and Extended Arabic-Indic (Persian) digits to ASCII digits
+func Digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '٠' && r <= '٩':
+			return '0' + (r - '٠')
+		case r >= '۰' && r <= '۹':
+			return '0' + (r - '۰')
+		}
+		return r
+	}, s)
+}
+
+// Text lowercases s, converts digits to ASCII and normalizes Arabic script
+func Text(s string) string {
+	return strings.ToLower(Arabic(Digits(s)))
+}
+
+// apostrophes are dropped inside words so that e.g. "Mas'ud" stays a single token
+var apostrophes = strings.NewReplacer("'", "", "`", "", "’", "", "‘", "")
+
+// Tokenize normalizes s and splits it into words, treating everything that is
+// not a letter or digit as a separator
+func Tokenize(s string) []string {
+	return strings.FieldsFunc(apostrophes.Replace(Text(s)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// SearchText is the form in which text is indexed for full-text search: its
+// tokens joined by spaces. Queries are tokenized the same way, so that e.g.
+// "Mas'ud" is indexed and searched as "masud".
+func SearchText(s string) string {
+	return strings.Join(Tokenize(s), " ")
+}
+
+// arabicProclitics are the prefixes attached to Arabic words: conjunctions, then prepositions
+var arabicProclitics = []string{"وب", "ول", "وك", "فب", "فل", "لل", "و", "ف", "ب", "ك", "ل"}
+
+// WordForms returns a normalized token together with its forms without Arabic
+// proclitics and article, so that "والصلاه" also matches "الصلاه" and "صلاه"
+func WordForms(token string) []string {
+	forms := []string{token, StripArticle(token)}
+	for _, proclitic := range arabicProclitics {
+		if rest := strings.TrimPrefix(token, proclitic); rest != token && len([]rune(rest)) >= 2 {
+			forms = append(forms, rest, StripArticle(rest))
+		}
+	}
+	return forms
+}
+
+// TokenForms returns the WordForms of each token
+func TokenForms(tokens []string) [][]string {
+	forms := make([][]string, len(tokens))
+	for i, token := range tokens {
+		forms[i] = WordForms(token)
+	}
+	return forms
+}
+
+// Term splits a search term into normalized words without the article, to be
+// matched against word forms. A trailing "*" sets prefix, so that the last
+// word matches any word starting with it.
+func Term(term string) (words []string, prefix bool) {
+	term = strings.TrimSpace(term)
+	prefix = strings.HasSuffix(term, "*")
+	for _, word := range Tokenize(strings.TrimSuffix(term, "*")) {
+		words = append(words, StripArticle(word))
+	}
+	return words, prefix
+}
+
+// MatchForms reports whether the words of a term match the words whose forms
+// start at index i, the last one by prefix when prefix is set
+func MatchForms(forms [][]string, i int, words []string, prefix bool) bool {
+	if i+len(words) > len(forms) {
+		return false
+	}
+	last := len(words) - 1
+	for j, word := range words {
+		if !HasForm(forms[i+j], word, prefix && j == last) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasForm reports whether any of a word's forms equals word, or starts with it when prefix is set
+func HasForm(forms []string, word string, prefix bool) bool {
+	for _, form := range forms {
+		if form == word || (prefix && strings.HasPrefix(form, word)) {
+			return true
+		}
+	}
+	return false
+}
+
+// StripArticle removes the Arabic definite article "ال" from a word
+func StripArticle(word string) string {
+	if rest := strings.TrimPrefix(word, "ال"); rest != word && len([]rune(rest)) >= 2 {
+		return rest
+	}
+	return word
+}
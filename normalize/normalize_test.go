@@ -0,0 +1,75 @@
+package normalize
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"إِنَّمَا الْأَعْمَالُ بِالنِّيَّاتِ", []string{"انما", "الاعمال", "بالنيات"}},
+		{"الصَّلَاةُ ـــ مُؤْمِنٌ سُئِلَ", []string{"الصلاه", "مومن", "سيل"}},
+		{"Ibnu Mas'ud, Mas’ud dan Mas`ud.", []string{"ibnu", "masud", "masud", "dan", "masud"}},
+		{"no. ١٢٣ / ۴۵", []string{"no", "123", "45"}},
+		{"  ", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := Tokenize(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	if got := SearchText("Shalat  MALAM!"); got != "shalat malam" {
+		t.Errorf("SearchText = %q, want %q", got, "shalat malam")
+	}
+}
+
+func TestTerm(t *testing.T) {
+	tests := []struct {
+		term   string
+		words  []string
+		prefix bool
+	}{
+		{"الصلاة", []string{"صلاه"}, false},
+		{" rasul* ", []string{"rasul"}, true},
+		{"قال رسول الله", []string{"قال", "رسول", "له"}, false},
+		{"*", nil, true},
+	}
+
+	for _, tt := range tests {
+		words, prefix := Term(tt.term)
+		if !reflect.DeepEqual(words, tt.words) || prefix != tt.prefix {
+			t.Errorf("Term(%q) = %q, %v; want %q, %v", tt.term, words, prefix, tt.words, tt.prefix)
+		}
+	}
+}
+
+func TestMatchForms(t *testing.T) {
+	forms := TokenForms(Tokenize("وَالصَّلَاةِ خَيْرٌ مِنَ النَّوْمِ"))
+
+	tests := []struct {
+		term string
+		at   int
+		want bool
+	}{
+		{"صلاة", 0, true},
+		{"الصلاة", 0, true},
+		{"صلا", 0, false},
+		{"صلا*", 0, true},
+		{"خير من", 1, true},
+		{"خير من", 0, false},
+		{"من النوم", 2, true},
+		{"النوم خير", 3, false},
+	}
+
+	for _, tt := range tests {
+		words, prefix := Term(tt.term)
+		if got := MatchForms(forms, tt.at, words, prefix); got != tt.want {
+			t.Errorf("MatchForms(%q at %d) = %v, want %v", tt.term, tt.at, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package normalize
+
+// stopwords are frequent function words in the Indonesian translations and the
+// normalized Arabic text, including the formulaic parts of the isnad
+var stopwords = toSet(
+	// Indonesian
+	"yang", "dan", "di", "dari", "ke", "kepada", "kepadaku", "kami", "kita", "aku", "saya", "ia", "dia",
+	"beliau", "engkau", "kamu", "kalian", "mereka", "itu", "ini", "dengan", "untuk", "pada", "dalam",
+	"tidak", "telah", "akan", "bahwa", "bahwasanya", "adalah", "atau", "juga", "ada", "berkata", "bersabda",
+	"menceritakan", "mengabarkan", "memberitakan", "bin", "binti", "radliyallahu", "anhu", "anha", "anhum",
+	"shallallahu", "alaihi", "wasallam", "lalu", "maka", "kemudian", "seraya", "apabila", "jika", "jikalau",
+	"ketika", "tatkala", "seorang", "orang", "hal", "tersebut", "sesuatu", "hingga", "sampai", "oleh",
+	"karena", "sebuah", "para", "tentang", "apa", "siapa", "sungguh", "sesungguhnya", "pun", "lah", "ya",
+	"wahai", "begitu", "demikian", "sebelum", "setelah", "sesudah", "bagi", "atas", "antara", "tanpa",
+	"lebih", "sangat", "hanya", "sudah", "belum", "masih", "boleh", "dapat", "bisa", "pernah", "sama",
+	"semua", "setiap", "beberapa", "lain", "diri", "bahkan", "namun", "tetapi", "agar", "supaya",
+	"sedang", "sedangkan", "seperti", "sebagaimana", "kecuali", "mengenai", "yaitu", "ialah", "sendiri",
+	"nya", "mu", "ku", "kah", "tiba", "katanya", "berkatalah", "mendengar", "menjawab", "bertanya",
+	"kepadanya", "apakah", "mengatakan", "suatu", "nabi", "rasulullah", "radliallahu", "radhiyallahu",
+	"sallallahu", "shallallahu'alaihi", "shalla", "allahu", "sallam", "wa", "alaihis", "salam",
+	// Arabic (normalized: no diacritics, folded alef, ya and ta marbuta)
+	"حدثنا", "حدثني", "اخبرنا", "اخبرني", "انبانا", "سمعت", "عن", "ان", "قال", "قالت", "فقال", "وقال",
+	"فقالت", "في", "من", "على", "علي", "الي", "ما", "لا", "و", "ثم", "هذا", "هذه", "ذلك", "كان", "كانت",
+	"هو", "هي", "يا", "رسول", "الله", "صلي", "عليه", "وسلم", "رضي", "عنه", "عنها", "بن", "ابن", "ابي",
+	"ابو", "او", "اذا", "قد", "لم", "لن", "التي", "الذي", "مع", "كل", "بعد", "قبل", "حتي", "انه", "انها",
+	"به", "له", "لها", "لهم", "بها", "فيه", "منه", "منها", "ولا", "وهو", "فلما", "لما", "يقول", "يعني",
+	"النبي", "بنت", "اما", "الا", "اني", "انا", "نحن", "انت", "هم", "ذا", "عند", "عندي", "فان", "وان",
+)
+
+// IsStopword reports whether a normalized, lowercased word is a stopword
+func IsStopword(word string) bool {
+	return stopwords[word]
+}
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hadith-api/models"
+)
+
+// clustersFile is the name of the clustering output in the meta directory
+const clustersFile = "clusters.json"
+
+// GetClusters returns the clusters from the last clustering run without their hadith lists
+func (r *FileRepository) GetClusters() ([]models.Cluster, error) {
+	set, err := r.loadClusters()
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make([]models.Cluster, len(set.Clusters))
+	for i, cluster := range set.Clusters {
+		cluster.Hadiths = nil
+		clusters[i] = cluster
+	}
+
+	return clusters, nil
+}
+
+// GetCluster returns a single cluster including the hadith numbers per narrator
+func (r *FileRepository) GetCluster(id int) (*models.Cluster, error) {
+	set, err := r.loadClusters()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cluster := range set.Clusters {
+		if cluster.ID == id {
+			return &cluster, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cluster %d not found", id)
+}
+
+// loadClusters loads the clustering output from the meta directory.
+// A missing file is not an error; there are then no clusters.
+func (r *FileRepository) loadClusters() (*models.ClusterSet, error) {
+	r.mu.RLock()
+	set := r.clusters
+	r.mu.RUnlock()
+	if set != nil {
+		return set, nil
+	}
+
+	set = &models.ClusterSet{Clusters: []models.Cluster{}}
+
+	filePath := filepath.Join(r.DataDir, metaDir, clustersFile)
+	fileData, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read clusters: %w", err)
+	}
+
+	if err == nil {
+		if err := json.Unmarshal(fileData, set); err != nil {
+			return nil, fmt.Errorf("failed to parse clusters: %w", err)
+		}
+	}
+
+	r.mu.Lock()
+	r.clusters = set
+	r.mu.Unlock()
+
+	return set, nil
+}
+
+// WriteClusterFile writes a clustering result, one cluster's hadith list per line
+func WriteClusterFile(path string, set *models.ClusterSet) error {
+	header, err := json.Marshal(struct {
+		GeneratedAt string `json:"generated_at"`
+		Field       string `json:"field"`
+		K           int    `json:"k"`
+		Seed        int64  `json:"seed"`
+	}{set.GeneratedAt, set.Field, set.K, set.Seed})
+	if err != nil {
+		return fmt.Errorf("failed to encode clusters: %w", err)
+	}
+
+	// Reuse the header fields and append the clusters array
+	out := header[:len(header)-1]
+	out = append(out, []byte(",\"clusters\":[")...)
+	for i, cluster := range set.Clusters {
+		encoded, err := json.Marshal(cluster)
+		if err != nil {
+			return fmt.Errorf("failed to encode cluster %d: %w", cluster.ID, err)
+		}
+		if i > 0 {
+			out = append(out, ',')
+		}
+		out = append(out, '\n')
+		out = append(out, encoded...)
+	}
+	out = append(out, []byte("\n]}\n")...)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		return fmt.Errorf("failed to write cluster file %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"hash/fnv"
+	"sort"
+
+	"github.com/hadith-api/models"
+)
+
+// corpus is the flattened list of every hadith across all narrators
+type corpus struct {
+	refs        []models.HadithReference
+	fingerprint uint64
+}
+
+// GetCorpus returns a reference to every hadith across all narrators, ordered by
+// narrator slug and then collection order, together with a fingerprint of the
+// corpus content that changes whenever any collection does
+func (r *FileRepository) GetCorpus() ([]models.HadithReference, uint64, error) {
+	r.mu.RLock()
+	c := r.corpus
+	r.mu.RUnlock()
+	if c != nil {
+		return c.refs, c.fingerprint, nil
+	}
+
+	narrators, err := r.GetAvailableNarrators()
+	if err != nil {
+		return nil, 0, err
+	}
+	sort.Strings(narrators)
+
+	hash := fnv.New64a()
+	c = &corpus{}
+	for _, narrator := range narrators {
+		hadiths, err := r.loadNarratorData(narrator)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		hash.Write([]byte(narrator))
+		for _, h := range hadiths {
+			c.refs = append(c.refs, models.HadithReference{Slug: narrator, Number: h.Number})
+			hash.Write([]byte(h.Number.String()))
+			hash.Write([]byte(h.Arab))
+		}
+	}
+	c.fingerprint = hash.Sum64()
+
+	r.mu.Lock()
+	r.corpus = c
+	r.mu.Unlock()
+
+	return c.refs, c.fingerprint, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+)
+
+// reversedSource lists the narrators of a source in reverse order
+type reversedSource struct {
+	collectionSource
+}
+
+func (s reversedSource) narrators() ([]string, error) {
+	narrators, err := s.collectionSource.narrators()
+	reversed := make([]string, len(narrators))
+	for i, narrator := range narrators {
+		reversed[len(narrators)-1-i] = narrator
+	}
+	return reversed, err
+}
+
+func TestGetCorpusNarratorOrder(t *testing.T) {
+	repo := NewFileRepository(fixtureDir)
+	refs, fingerprint, err := repo.GetCorpus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refs) != 9 || refs[0].Slug != "alpha" {
+		t.Fatalf("GetCorpus = %+v, want the 9 hadiths of alpha and beta", refs)
+	}
+
+	reversed := NewFileRepository(fixtureDir)
+	reversed.source = reversedSource{reversed.source}
+	if narrators, _ := reversed.GetAvailableNarrators(); narrators[0] != "beta" {
+		t.Fatalf("GetAvailableNarrators = %v, want beta first", narrators)
+	}
+
+	got, gotFingerprint, err := reversed.GetCorpus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, refs) || gotFingerprint != fingerprint {
+		t.Errorf("GetCorpus with the narrators reversed = %+v, %x; want %+v, %x", got, gotFingerprint, refs, fingerprint)
+	}
+}
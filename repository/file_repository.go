@@ -1,12 +1,14 @@
 package repository
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/hadith-api/models"
 )
@@ -14,7 +16,31 @@ import (
 // FileRepository handles loading and retrieving hadith data from JSON files
 type FileRepository struct {
 	DataDir string
-	cache   map[string][]models.Hadith
+	// source reads the collections; metadata is always read from the meta directory
+	source collectionSource
+	// cacheCollections keeps the loaded collections in memory. The database
+	// backends leave it off, so that whole collections only pass through the
+	// operations that need them.
+	cacheCollections bool
+	*repositoryState
+}
+
+// repositoryState holds what a FileRepository has loaded or computed. It is
+// shared by the views of a repository bound to another source.
+type repositoryState struct {
+	mu        sync.RWMutex
+	cache     map[string][]models.Hadith
+	numbering map[string]map[string]numberingScheme
+	manifest  *collectionManifest
+	corpus    *corpus
+	occasions []models.Occasion
+	taxonomy  []models.Topic
+	clusters  *models.ClusterSet
+	// stats are computed per narrator when they are first requested
+	stats       map[string]*collectionStats
+	corpusStats *models.Stats
+	topicCounts map[string]int
+	revisions   *revisionHistory
 }
 
 // Improved FileRepository initialization with better error handling
@@ -51,18 +77,81 @@ func NewFileRepository(dataDir string) *FileRepository {
 		}
 	}
 
+	repo := newFileRepository(dataDir, fileSource{dir: dataDir})
+	repo.cacheCollections = true
+	return repo
+}
+
+// newFileRepository creates a repository reading its collections from source
+func newFileRepository(dataDir string, source collectionSource) *FileRepository {
 	return &FileRepository{
 		DataDir: dataDir,
-		cache:   make(map[string][]models.Hadith),
+		source:  source,
+		repositoryState: &repositoryState{
+			cache:     make(map[string][]models.Hadith),
+			numbering: make(map[string]map[string]numberingScheme),
+			stats:     make(map[string]*collectionStats),
+		},
 	}
 }
 
+// withSource returns a view of the repository that reads its collections
+// from source and shares everything loaded and computed with r
+func (r *FileRepository) withSource(source collectionSource) *FileRepository {
+	view := *r
+	view.source = source
+	return &view
+}
+
+// collectionSource reads the narrators and their hadiths in collection order
+type collectionSource interface {
+	narrators() ([]string, error)
+	hadiths(narrator string) ([]models.Hadith, error)
+}
+
+// fileSource reads a JSON file per narrator from the data directory
+type fileSource struct {
+	dir string
+}
+
 // GetAvailableNarrators with improved error handling
 func (r *FileRepository) GetAvailableNarrators() ([]string, error) {
-	files, err := os.ReadDir(r.DataDir)
+	narrators, err := r.source.narrators()
+	if err != nil {
+		return nil, err
+	}
+
+	// Return empty slice instead of nil if no narrators found
+	if len(narrators) == 0 {
+		return []string{}, nil
+	}
+
+	return narrators, nil
+}
+
+// checkNarratorExists returns an error when the narrator is not available,
+// without loading its hadiths
+func (r *FileRepository) checkNarratorExists(narrator string) error {
+	narrators, err := r.GetAvailableNarrators()
+	if err != nil {
+		return err
+	}
+
+	for _, available := range narrators {
+		if available == narrator {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("narrator %s not found", narrator)
+}
+
+// narrators lists the JSON files of the data directory
+func (s fileSource) narrators() ([]string, error) {
+	files, err := os.ReadDir(s.dir)
 	if err != nil {
 		// More descriptive error message
-		return nil, fmt.Errorf("failed to read data directory %s: %w", r.DataDir, err)
+		return nil, fmt.Errorf("failed to read data directory %s: %w", s.dir, err)
 	}
 
 	var narrators []string
@@ -73,12 +162,30 @@ func (r *FileRepository) GetAvailableNarrators() ([]string, error) {
 		}
 	}
 
-	// Return empty slice instead of nil if no narrators found
-	if len(narrators) == 0 {
-		return []string{}, nil
+	return narrators, nil
+}
+
+// hadiths reads and parses the JSON file of a narrator
+func (s fileSource) hadiths(narrator string) ([]models.Hadith, error) {
+	// Check if the file exists
+	filePath := filepath.Join(s.dir, fmt.Sprintf("%s.json", narrator))
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("narrator %s not found", narrator)
 	}
 
-	return narrators, nil
+	// Read the file
+	fileData, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file for narrator %s: %w", narrator, err)
+	}
+
+	// Parse the JSON
+	var hadiths []models.Hadith
+	if err := json.Unmarshal(fileData, &hadiths); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON for narrator %s: %w", narrator, err)
+	}
+
+	return hadiths, nil
 }
 
 // GetHadithsByNarrator returns all hadiths from a specific narrator
@@ -89,14 +196,22 @@ func (r *FileRepository) GetHadithsByNarrator(narrator string, params models.Que
 		return nil, 0, err
 	}
 
-	// Apply filtering if query parameter is provided
+	// Apply filtering if query, grade or topic parameters are provided
 	var filteredHadiths []models.Hadith
-	if params.Query != "" {
+	if params.Query != "" || params.Grade != "" || params.Topic != "" {
 		for _, h := range hadiths {
-			if strings.Contains(strings.ToLower(h.ID), strings.ToLower(params.Query)) ||
-				strings.Contains(strings.ToLower(h.Arab), strings.ToLower(params.Query)) {
-				filteredHadiths = append(filteredHadiths, h)
+			if params.Query != "" &&
+				!strings.Contains(strings.ToLower(h.ID), strings.ToLower(params.Query)) &&
+				!strings.Contains(strings.ToLower(h.Arab), strings.ToLower(params.Query)) {
+				continue
+			}
+			if params.Grade != "" && !h.HasGrade(params.Grade) {
+				continue
 			}
+			if params.Topic != "" && !h.HasTopic(params.Topic) {
+				continue
+			}
+			filteredHadiths = append(filteredHadiths, h)
 		}
 	} else {
 		filteredHadiths = hadiths
@@ -127,7 +242,7 @@ func (r *FileRepository) GetHadithsByNarrator(narrator string, params models.Que
 }
 
 // GetHadithByNumber returns a specific hadith by narrator and number
-func (r *FileRepository) GetHadithByNumber(narrator string, number int) (*models.Hadith, error) {
+func (r *FileRepository) GetHadithByNumber(narrator string, number models.Number) (*models.Hadith, error) {
 	// Load data for the narrator
 	hadiths, err := r.loadNarratorData(narrator)
 	if err != nil {
@@ -141,36 +256,159 @@ func (r *FileRepository) GetHadithByNumber(narrator string, number int) (*models
 		}
 	}
 
-	return nil, fmt.Errorf("hadith number %d not found for narrator %s", number, narrator)
+	return nil, fmt.Errorf("hadith number %s not found for narrator %s", number, narrator)
+}
+
+// GetHadithByBook returns the hadith cited by its number within a book, the
+// book being the nth Book heading of the collection. Within a book the
+// hadiths are numbered from 1 in the same way as the collection, so "1/23"
+// is the 23rd hadith under the first heading and "1/23a" the one suffixed
+// after it. Collections without Book headings cannot be cited this way.
+func (r *FileRepository) GetHadithByBook(narrator string, book int, number models.Number) (*models.Hadith, error) {
+	// Load data for the narrator
+	hadiths, err := r.loadNarratorData(narrator)
+	if err != nil {
+		return nil, err
+	}
+
+	heading, seen := "", 0
+	for i, h := range hadiths {
+		if h.Book == "" || h.Book == heading {
+			continue
+		}
+		heading = h.Book
+		seen++
+		if seen < book {
+			continue
+		}
+
+		// Rebase the number onto the one before the book's first hadith
+		offset := h.Number.Value - 1
+		for _, h := range hadiths[i:] {
+			if h.Book != heading {
+				break
+			}
+			if h.Number.Value-offset == number.Value && h.Number.Suffix == number.Suffix {
+				return &h, nil
+			}
+		}
+		return nil, fmt.Errorf("hadith number %s not found in book %d for narrator %s", number, book, narrator)
+	}
+
+	return nil, fmt.Errorf("book %d not found for narrator %s", book, narrator)
+}
+
+// GetHadithRange returns the hadiths of a narrator numbered from..to inclusive, in collection order
+func (r *FileRepository) GetHadithRange(narrator string, from, to models.Number) ([]models.Hadith, error) {
+	// Load data for the narrator
+	hadiths, err := r.loadNarratorData(narrator)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.Hadith{}
+	for _, h := range hadiths {
+		if !h.Number.Less(from) && !to.Less(h.Number) {
+			result = append(result, h)
+		}
+	}
+
+	return result, nil
 }
 
-// loadNarratorData loads hadith data for a specific narrator from the JSON file
+// GetCollection returns metadata for a narrator's collection, including a
+// summary of how many hadiths carry each grade. A hadith graded differently by
+// several graders is counted once under each distinct grade.
+func (r *FileRepository) GetCollection(narrator string) (*models.Collection, error) {
+	hadiths, err := r.loadNarratorData(narrator)
+	if err != nil {
+		return nil, err
+	}
+
+	grades := make(map[string]int)
+	for _, h := range hadiths {
+		if len(h.Grades) == 0 {
+			grades[models.GradeUngraded]++
+			continue
+		}
+
+		seen := make(map[string]bool)
+		for _, g := range h.Grades {
+			grade := models.NormalizeGrade(g.Grade)
+			if !seen[grade] {
+				seen[grade] = true
+				grades[grade]++
+			}
+		}
+	}
+
+	return r.newCollection(narrator, len(hadiths), grades)
+}
+
+// WithContext returns the repository itself, which reads files rather than
+// running queries
+func (r *FileRepository) WithContext(ctx context.Context) Repository {
+	return r
+}
+
+// newCollection completes the counts of a collection with its numbering
+// schemes and the metadata configured in the manifest
+func (r *FileRepository) newCollection(narrator string, total int, grades map[string]int) (*models.Collection, error) {
+	schemes, err := r.GetNumberingSchemes(narrator)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := r.loadManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := manifest.Collections[narrator]
+
+	return &models.Collection{
+		Slug:         narrator,
+		Name:         entry.Name,
+		Title:        entry.Title,
+		Compiler:     entry.Compiler,
+		TotalHadiths: total,
+		Grades:       grades,
+		Schemes:      schemes,
+		Aliases:      entry.Aliases,
+	}, nil
+}
+
+// loadNarratorData loads hadith data for a specific narrator from its source
 func (r *FileRepository) loadNarratorData(narrator string) ([]models.Hadith, error) {
 	// Check if the data is already cached
-	if hadiths, ok := r.cache[narrator]; ok {
+	r.mu.RLock()
+	hadiths, ok := r.cache[narrator]
+	r.mu.RUnlock()
+	if ok {
 		return hadiths, nil
 	}
 
-	// Check if the file exists
-	filePath := filepath.Join(r.DataDir, fmt.Sprintf("%s.json", narrator))
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("narrator %s not found", narrator)
+	hadiths, err := r.source.hadiths(narrator)
+	if err != nil {
+		return nil, err
 	}
 
-	// Read the file
-	fileData, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file for narrator %s: %w", narrator, err)
+	// Merge tags from the sidecar tag file
+	if err := r.applyTags(narrator, hadiths); err != nil {
+		return nil, err
 	}
 
-	// Parse the JSON
-	var hadiths []models.Hadith
-	if err := json.Unmarshal(fileData, &hadiths); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON for narrator %s: %w", narrator, err)
+	// Hash the content for incremental sync
+	for i := range hadiths {
+		hadiths[i].Hash = hadiths[i].ContentHash()
 	}
 
 	// Cache the data
-	r.cache[narrator] = hadiths
+	if r.cacheCollections {
+		r.mu.Lock()
+		r.cache[narrator] = hadiths
+		r.mu.Unlock()
+	}
 
 	return hadiths, nil
 }
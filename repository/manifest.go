@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/normalize"
+)
+
+// metaDir is the subdirectory of the data directory holding collection metadata
+const metaDir = "meta"
+
+// collectionManifest is the on-disk format of meta/collections.json
+type collectionManifest struct {
+	Collections map[string]manifestEntry `json:"collections"`
+}
+
+// manifestEntry holds the metadata configured for a single collection
+type manifestEntry struct {
+	Name     string   `json:"name"`
+	Title    string   `json:"title"`
+	Compiler string   `json:"compiler"`
+	Aliases  []string `json:"aliases"`
+}
+
+// GetAliases returns the aliases configured for each available narrator
+func (r *FileRepository) GetAliases() (map[string][]string, error) {
+	manifest, err := r.loadManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	narrators, err := r.GetAvailableNarrators()
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := make(map[string][]string, len(narrators))
+	for _, narrator := range narrators {
+		aliases[narrator] = manifest.Collections[narrator].Aliases
+	}
+
+	return aliases, nil
+}
+
+// GetCollectionInfo returns the metadata of a narrator's collection configured
+// in the manifest, with its numbering schemes. Unlike GetCollection it does not
+// read the hadiths, so the hadith and grade counts are left empty.
+func (r *FileRepository) GetCollectionInfo(narrator string) (*models.Collection, error) {
+	if err := r.checkNarratorExists(narrator); err != nil {
+		return nil, err
+	}
+
+	return r.newCollection(narrator, 0, nil)
+}
+
+// ResolveNarrator maps a slug, matched case-insensitively against the narrator
+// names and their configured aliases, onto the canonical narrator slug
+func (r *FileRepository) ResolveNarrator(slug string) (string, error) {
+	aliases, err := r.GetAliases()
+	if err != nil {
+		return "", err
+	}
+
+	// Exact matches take precedence over aliases
+	if _, ok := aliases[slug]; ok {
+		return slug, nil
+	}
+
+	// Narrators are tried in sorted order, their names before any alias, so
+	// that an alias shared by two collections always resolves the same way
+	narrators := make([]string, 0, len(aliases))
+	for narrator := range aliases {
+		narrators = append(narrators, narrator)
+	}
+	sort.Strings(narrators)
+
+	key := aliasKey(slug)
+	for _, narrator := range narrators {
+		if aliasKey(narrator) == key {
+			return narrator, nil
+		}
+	}
+	for _, narrator := range narrators {
+		for _, name := range aliases[narrator] {
+			if aliasKey(name) == key {
+				return narrator, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("narrator %s not found", slug)
+}
+
+// aliasKey normalizes a slug or alias so that "Imam-Malik" and "imam malik" compare equal
+func aliasKey(name string) string {
+	return strings.Join(normalize.Tokenize(name), " ")
+}
+
+// loadManifest loads the collection manifest from the meta directory.
+// A missing manifest is not an error; collections then have no extra metadata.
+func (r *FileRepository) loadManifest() (*collectionManifest, error) {
+	r.mu.RLock()
+	manifest := r.manifest
+	r.mu.RUnlock()
+	if manifest != nil {
+		return manifest, nil
+	}
+
+	manifest = &collectionManifest{}
+
+	filePath := filepath.Join(r.DataDir, metaDir, "collections.json")
+	fileData, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read collection manifest: %w", err)
+	}
+
+	if err == nil {
+		if err := json.Unmarshal(fileData, manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse collection manifest: %w", err)
+		}
+	}
+
+	r.mu.Lock()
+	r.manifest = manifest
+	r.mu.Unlock()
+
+	return manifest, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveNarrator(t *testing.T) {
+	dir := copyFixture(t)
+
+	// beta claims alpha's name and shares an alias with it
+	manifest := `{"collections": {
+		"alpha": {"name": "Alpha", "aliases": ["al alpha", "shared"]},
+		"beta": {"name": "Beta", "aliases": ["Alpha", "shared", "Imam-Beta"]}
+	}}`
+	if err := os.WriteFile(filepath.Join(dir, metaDir, "collections.json"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewFileRepository(dir)
+
+	tests := []struct {
+		slug string
+		want string
+	}{
+		{"beta", "beta"},
+		{"ALPHA", "alpha"},
+		{"Al-Alpha", "alpha"},
+		{"imam beta", "beta"},
+		{"shared", "alpha"},
+	}
+
+	for _, tt := range tests {
+		// Repeated, as map iteration would only fail some of the time
+		for i := 0; i < 20; i++ {
+			got, err := repo.ResolveNarrator(tt.slug)
+			if err != nil || got != tt.want {
+				t.Fatalf("ResolveNarrator(%q) = %q, %v; want %q", tt.slug, got, err, tt.want)
+			}
+		}
+	}
+
+	if _, err := repo.ResolveNarrator("gamma"); err == nil {
+		t.Error("ResolveNarrator(gamma): want an error")
+	}
+}
+
+func TestGetCollectionInfo(t *testing.T) {
+	repo := NewFileRepository(fixtureDir)
+
+	collection, err := repo.GetCollectionInfo("alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if collection.Name != "Alpha" || collection.Title != "Kitab Alpha" || collection.Compiler != "Imam Alpha" {
+		t.Errorf("GetCollectionInfo(alpha) = %+v, want the manifest metadata", collection)
+	}
+
+	repo.mu.RLock()
+	loaded := len(repo.cache)
+	repo.mu.RUnlock()
+	if loaded != 0 {
+		t.Errorf("GetCollectionInfo loaded %d collections, want none", loaded)
+	}
+
+	if _, err := repo.GetCollectionInfo("gamma"); err == nil {
+		t.Error("GetCollectionInfo(gamma): want an error")
+	}
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hadith-api/models"
+)
+
+// numberingFile is the on-disk format of a collection's alternate numbering table.
+// Each scheme maps a number in that scheme onto the number used in the data file.
+type numberingFile struct {
+	Schemes map[string]map[string]models.Number `json:"schemes"`
+}
+
+// numberingScheme maps numbers in an alternate scheme onto the default numbering
+type numberingScheme map[models.Number]models.Number
+
+// GetNumberingSchemes returns the numbering schemes known for a narrator, starting with the default scheme
+func (r *FileRepository) GetNumberingSchemes(narrator string) ([]string, error) {
+	schemes, err := r.loadNumbering(narrator)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return append([]string{models.DefaultScheme}, names...), nil
+}
+
+// ResolveNumber converts a number in the given scheme into the collection's default numbering
+func (r *FileRepository) ResolveNumber(narrator, scheme string, number models.Number) (models.Number, error) {
+	if scheme == "" || scheme == models.DefaultScheme {
+		return number, nil
+	}
+
+	schemes, err := r.loadNumbering(narrator)
+	if err != nil {
+		return models.Number{}, err
+	}
+
+	table, ok := schemes[scheme]
+	if !ok {
+		return models.Number{}, fmt.Errorf("numbering scheme %s not found for narrator %s", scheme, narrator)
+	}
+
+	resolved, ok := table[number]
+	if !ok {
+		return models.Number{}, fmt.Errorf("hadith number %s not found in scheme %s for narrator %s", number, scheme, narrator)
+	}
+
+	return resolved, nil
+}
+
+// hadithLookup is the GetHadithByNumber of a repository. The database
+// repositories pass their own, as the embedded FileRepository would load
+// the whole collection to find a single hadith.
+type hadithLookup func(narrator string, number models.Number) (*models.Hadith, error)
+
+// GetHadithByScheme returns a specific hadith by narrator and its number in the given scheme
+func (r *FileRepository) GetHadithByScheme(narrator, scheme string, number models.Number) (*models.Hadith, error) {
+	return r.hadithByScheme(r.GetHadithByNumber, narrator, scheme, number)
+}
+
+// hadithByScheme resolves a number in the given scheme and looks the hadith up with lookup
+func (r *FileRepository) hadithByScheme(lookup hadithLookup, narrator, scheme string, number models.Number) (*models.Hadith, error) {
+	resolved, err := r.ResolveNumber(narrator, scheme, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return lookup(narrator, resolved)
+}
+
+// GetConcordance returns the numbers a hadith carries in every known scheme, or
+// only in the target scheme when one is given
+func (r *FileRepository) GetConcordance(narrator, scheme string, number models.Number, target string) (*models.Concordance, error) {
+	return r.concordance(r.GetHadithByNumber, narrator, scheme, number, target)
+}
+
+// concordance implements GetConcordance, looking the hadith up with lookup
+func (r *FileRepository) concordance(lookup hadithLookup, narrator, scheme string, number models.Number, target string) (*models.Concordance, error) {
+	hadith, err := r.hadithByScheme(lookup, narrator, scheme, number)
+	if err != nil {
+		return nil, err
+	}
+
+	schemes, err := r.loadNumbering(narrator)
+	if err != nil {
+		return nil, err
+	}
+
+	if target != "" && target != models.DefaultScheme {
+		if _, ok := schemes[target]; !ok {
+			return nil, fmt.Errorf("numbering scheme %s not found for narrator %s", target, narrator)
+		}
+	}
+
+	references := make(map[string][]models.Number)
+	if target == "" || target == models.DefaultScheme {
+		references[models.DefaultScheme] = []models.Number{hadith.Number}
+	}
+
+	for name, table := range schemes {
+		if target != "" && target != name {
+			continue
+		}
+
+		numbers := []models.Number{}
+		for alternate, primary := range table {
+			if primary == hadith.Number {
+				numbers = append(numbers, alternate)
+			}
+		}
+		sort.Slice(numbers, func(i, j int) bool { return numbers[i].Less(numbers[j]) })
+		references[name] = numbers
+	}
+
+	if scheme == "" {
+		scheme = models.DefaultScheme
+	}
+
+	return &models.Concordance{
+		Scheme:     scheme,
+		Number:     number,
+		References: references,
+	}, nil
+}
+
+// loadNumbering loads the alternate numbering table for a narrator from the meta directory.
+// A missing table is not an error; the collection then only has its default scheme.
+func (r *FileRepository) loadNumbering(narrator string) (map[string]numberingScheme, error) {
+	r.mu.RLock()
+	schemes, ok := r.numbering[narrator]
+	r.mu.RUnlock()
+	if ok {
+		return schemes, nil
+	}
+
+	// Make sure the narrator itself exists
+	if err := r.checkNarratorExists(narrator); err != nil {
+		return nil, err
+	}
+
+	schemes = make(map[string]numberingScheme)
+
+	filePath := filepath.Join(r.DataDir, metaDir, "numbering", fmt.Sprintf("%s.json", narrator))
+	fileData, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read numbering table for narrator %s: %w", narrator, err)
+	}
+
+	if err == nil {
+		var table numberingFile
+		if err := json.Unmarshal(fileData, &table); err != nil {
+			return nil, fmt.Errorf("failed to parse numbering table for narrator %s: %w", narrator, err)
+		}
+
+		for name, entries := range table.Schemes {
+			scheme := make(numberingScheme, len(entries))
+			for alternate, primary := range entries {
+				number, err := models.ParseNumber(alternate)
+				if err != nil {
+					return nil, fmt.Errorf("invalid entry in scheme %s for narrator %s: %w", name, narrator, err)
+				}
+				scheme[number] = primary
+			}
+			schemes[name] = scheme
+		}
+	}
+
+	r.mu.Lock()
+	r.numbering[narrator] = schemes
+	r.mu.Unlock()
+
+	return schemes, nil
+}
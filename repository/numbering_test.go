@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hadith-api/models"
+)
+
+func TestNumberingSchemes(t *testing.T) {
+	repo := NewFileRepository(fixtureDir)
+
+	schemes, err := repo.GetNumberingSchemes("alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{models.DefaultScheme, "fuad", "print"}; !reflect.DeepEqual(schemes, want) {
+		t.Errorf("alpha schemes = %v, want %v", schemes, want)
+	}
+
+	// beta has no numbering table
+	schemes, err = repo.GetNumberingSchemes("beta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{models.DefaultScheme}; !reflect.DeepEqual(schemes, want) {
+		t.Errorf("beta schemes = %v, want %v", schemes, want)
+	}
+
+	tests := []struct {
+		scheme  string
+		number  string
+		want    string
+		wantErr bool
+	}{
+		{models.DefaultScheme, "7", "7", false},
+		{"fuad", "10", "1", false},
+		{"fuad", "12b", "2a", false},
+		{"print", "3", "2a", false},
+		{"print", "6", "5", false},
+		{"print", "7", "", true},
+		{"unknown", "1", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := repo.ResolveNumber("alpha", tt.scheme, mustNumber(t, tt.number))
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ResolveNumber(%s %s) = %s, want an error", tt.scheme, tt.number, got)
+			}
+			continue
+		}
+		if err != nil || got != mustNumber(t, tt.want) {
+			t.Errorf("ResolveNumber(%s %s) = %s, %v; want %s", tt.scheme, tt.number, got, err, tt.want)
+		}
+	}
+}
+
+func TestGetConcordance(t *testing.T) {
+	repo := NewFileRepository(fixtureDir)
+
+	concordance, err := repo.GetConcordance("alpha", "print", mustNumber(t, "3"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]models.Number{
+		models.DefaultScheme: {mustNumber(t, "2a")},
+		"fuad":               {mustNumber(t, "12a"), mustNumber(t, "12b")},
+		"print":              {mustNumber(t, "3")},
+	}
+	if !reflect.DeepEqual(concordance.References, want) {
+		t.Errorf("concordance of print 3 = %v, want %v", concordance.References, want)
+	}
+
+	if _, err := repo.GetConcordance("alpha", "", mustNumber(t, "1"), "unknown"); err == nil {
+		t.Error("unknown target scheme: want an error")
+	}
+}
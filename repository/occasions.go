@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hadith-api/models"
+)
+
+// occasionsFile is the on-disk format of meta/occasions.json
+type occasionsFile struct {
+	Occasions []models.Occasion `json:"occasions"`
+}
+
+// GetOccasions returns the configured occasions in priority order.
+// A missing configuration is not an error; there are then no occasions.
+func (r *FileRepository) GetOccasions() ([]models.Occasion, error) {
+	r.mu.RLock()
+	occasions := r.occasions
+	r.mu.RUnlock()
+	if occasions != nil {
+		return occasions, nil
+	}
+
+	occasions = []models.Occasion{}
+
+	filePath := filepath.Join(r.DataDir, metaDir, "occasions.json")
+	fileData, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read occasions: %w", err)
+	}
+
+	if err == nil {
+		var file occasionsFile
+		if err := json.Unmarshal(fileData, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse occasions: %w", err)
+		}
+		occasions = append(occasions, file.Occasions...)
+	}
+
+	r.mu.Lock()
+	r.occasions = occasions
+	r.mu.Unlock()
+
+	return occasions, nil
+}
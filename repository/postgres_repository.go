@@ -0,0 +1,538 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/normalize"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// postgresQueryTimeout bounds each query, also under a request context without a deadline
+const postgresQueryTimeout = 10 * time.Second
+
+// postgresMigrations create and evolve the database schema. Each runs once,
+// in order, and is recorded in schema_migrations.
+//
+// hadith_normalize folds text the way normalize.Text does, in the manner of
+// the unaccent extension: it strips Arabic diacritics and tatweel, folds alef,
+// ya, ta marbuta and hamza carrier variants, converts Arabic-Indic digits and
+// drops apostrophes. The search column indexes the normalized text, and
+// queries are normalized in Go before they are matched against it.
+var postgresMigrations = []string{
+	`CREATE FUNCTION hadith_normalize(text) RETURNS text
+		LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+		AS $$
+			SELECT lower(translate(
+				regexp_replace($1, '[\u064B-\u065F\u0670\u06D6-\u06ED\u0640]', '', 'g'),
+				E'\u0623\u0625\u0622\u0671\u0649\u0629\u0624\u0626' || '٠١٢٣٤٥٦٧٨٩۰۱۲۳۴۵۶۷۸۹' || E'\'\u0060\u2019\u2018',
+				E'\u0627\u0627\u0627\u0627\u064A\u0647\u0648\u064A' || '01234567890123456789'
+			))
+		$$;
+	CREATE TABLE narrators (
+		slug          text PRIMARY KEY,
+		total_hadiths integer NOT NULL
+	);
+	CREATE TABLE hadiths (
+		id            bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+		narrator      text NOT NULL REFERENCES narrators (slug) ON DELETE CASCADE,
+		position      integer NOT NULL,
+		number_value  integer NOT NULL,
+		number_suffix text COLLATE "C" NOT NULL,
+		arab          text NOT NULL,
+		translation   text NOT NULL,
+		grades        text[] NOT NULL,
+		topics        text[] NOT NULL,
+		data          jsonb NOT NULL,
+		search        tsvector GENERATED ALWAYS AS (
+			to_tsvector('simple', hadith_normalize(arab) || ' ' || hadith_normalize(translation))
+		) STORED,
+		UNIQUE (narrator, number_value, number_suffix)
+	);
+	CREATE INDEX hadiths_position ON hadiths (narrator, position);
+	CREATE INDEX hadiths_grades ON hadiths USING gin (grades);
+	CREATE INDEX hadiths_topics ON hadiths USING gin (topics);
+	CREATE INDEX hadiths_search ON hadiths USING gin (search);`,
+}
+
+// PostgresRepository serves the hadiths from a PostgreSQL database imported
+// from the collection files, through a connection pool. Like SQLiteRepository,
+// it answers narrators, listing, search, lookups by number and collection
+// metadata in the database, and leaves the operations that need whole
+// collections to the embedded FileRepository, which reads them from the
+// database without keeping them and reads the meta directory.
+type PostgresRepository struct {
+	*FileRepository
+	pool *pgxpool.Pool
+	ctx  context.Context
+}
+
+// postgresSource reads the collections of the embedded FileRepository from the
+// database, under ctx when it is set
+type postgresSource struct {
+	pool *pgxpool.Pool
+	ctx  context.Context
+}
+
+// NewPostgresRepository connects to the database at url, whose schema must be
+// up to date, with the metadata read from dataDir. The pool is configured
+// with the url's pool_* parameters, e.g. pool_max_conns=10.
+func NewPostgresRepository(ctx context.Context, dataDir, url string) (*PostgresRepository, error) {
+	pool, err := connectPostgres(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := postgresSchemaVersion(ctx, pool)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	if version != len(postgresMigrations) {
+		pool.Close()
+		return nil, fmt.Errorf("database has schema version %d instead of %d, run the migrate command",
+			version, len(postgresMigrations))
+	}
+
+	return &PostgresRepository{
+		FileRepository: newFileRepository(dataDir, postgresSource{pool: pool}),
+		pool:           pool,
+	}, nil
+}
+
+// MigratePostgres applies the migrations the database at url has not had yet
+// and returns the schema version. Concurrent runs wait for each other.
+func MigratePostgres(ctx context.Context, url string) (int, error) {
+	pool, err := connectPostgres(ctx, url)
+	if err != nil {
+		return 0, err
+	}
+	defer pool.Close()
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('hadith-api migrations'));
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    integer PRIMARY KEY,
+			applied_at timestamptz NOT NULL DEFAULT now()
+		)`); err != nil {
+		return 0, fmt.Errorf("failed to prepare migrations: %w", err)
+	}
+
+	var version int
+	if err := tx.QueryRow(ctx, `SELECT COALESCE(max(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version > len(postgresMigrations) {
+		return 0, fmt.Errorf("database has schema version %d, newer than this build", version)
+	}
+
+	for ; version < len(postgresMigrations); version++ {
+		if _, err := tx.Exec(ctx, postgresMigrations[version]); err != nil {
+			return 0, fmt.Errorf("failed to apply migration %d: %w", version+1, err)
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version+1); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// connectPostgres opens a connection pool and checks that the database is reachable
+func connectPostgres(ctx context.Context, url string) (*pgxpool.Pool, error) {
+	if url == "" {
+		return nil, errors.New("no database URL configured")
+	}
+
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure database connection: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return pool, nil
+}
+
+// postgresSchemaVersion returns the number of migrations applied, 0 for a new database
+func postgresSchemaVersion(ctx context.Context, pool *pgxpool.Pool) (int, error) {
+	var version int
+	err := pool.QueryRow(ctx, `SELECT CASE WHEN to_regclass('schema_migrations') IS NULL THEN 0
+		ELSE (SELECT COALESCE(max(version), 0) FROM schema_migrations) END`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// WithContext returns a repository whose database queries run under ctx,
+// e.g. a request context, so that they stop when it is canceled. This
+// includes those of the operations of the embedded FileRepository.
+func (r *PostgresRepository) WithContext(ctx context.Context) Repository {
+	bound := *r
+	bound.ctx = ctx
+	bound.FileRepository = r.FileRepository.withSource(postgresSource{pool: r.pool, ctx: ctx})
+	return &bound
+}
+
+// queryContext returns the context of a query: the bound context, or the
+// background one, limited to postgresQueryTimeout
+func (r *PostgresRepository) queryContext() (context.Context, context.CancelFunc) {
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, postgresQueryTimeout)
+}
+
+// Close closes the connection pool
+func (r *PostgresRepository) Close() {
+	r.pool.Close()
+}
+
+// Import replaces the hadiths in the database with those src serves, with
+// their sidecar tags and content hashes. It returns the number of hadiths imported.
+func (r *PostgresRepository) Import(ctx context.Context, src *FileRepository) (int, error) {
+	narrators, err := src.GetAvailableNarrators()
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `TRUNCATE hadiths, narrators RESTART IDENTITY`); err != nil {
+		return 0, fmt.Errorf("failed to clear database: %w", err)
+	}
+
+	total := 0
+	for _, narrator := range narrators {
+		hadiths, err := src.loadNarratorData(narrator)
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err := tx.Exec(ctx, `INSERT INTO narrators VALUES ($1, $2)`, narrator, len(hadiths)); err != nil {
+			return 0, fmt.Errorf("failed to import narrator %s: %w", narrator, err)
+		}
+
+		rows := make([][]interface{}, len(hadiths))
+		for i, h := range hadiths {
+			data, err := json.Marshal(h)
+			if err != nil {
+				return 0, fmt.Errorf("failed to encode hadith %s:%s: %w", narrator, h.Number, err)
+			}
+			rows[i] = []interface{}{narrator, i + 1, h.Number.Value, h.Number.Suffix, h.Arab, h.ID,
+				gradeList(h), topicList(h), string(data)}
+		}
+
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{"hadiths"},
+			[]string{"narrator", "position", "number_value", "number_suffix", "arab", "translation", "grades", "topics", "data"},
+			pgx.CopyFromRows(rows)); err != nil {
+			return 0, fmt.Errorf("failed to import hadiths of narrator %s: %w", narrator, err)
+		}
+		total += len(hadiths)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	// Refresh the planner statistics after replacing every row
+	if _, err := r.pool.Exec(ctx, `ANALYZE narrators, hadiths`); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// GetHadithsByNarrator returns a narrator's hadiths in collection order,
+// filtered and paginated in the database. The query matches words by prefix
+// through the full-text index, in either the Arabic text or the translation.
+func (r *PostgresRepository) GetHadithsByNarrator(narrator string, params models.QueryParams) ([]models.Hadith, int, error) {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	if err := r.checkNarrator(ctx, narrator); err != nil {
+		return nil, 0, err
+	}
+
+	where := []string{"narrator = $1"}
+	args := []interface{}{narrator}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if params.Query != "" {
+		match := tsQuery(params.Query)
+		if match == "" {
+			return []models.Hadith{}, 0, nil
+		}
+		where = append(where, "search @@ to_tsquery('simple', "+arg(match)+")")
+	}
+	if params.Grade != "" {
+		if grade := models.NormalizeGrade(params.Grade); grade == models.GradeUngraded {
+			where = append(where, "cardinality(grades) = 0")
+		} else {
+			where = append(where, "grades @> ARRAY["+arg(grade)+"::text]")
+		}
+	}
+	if params.Topic != "" {
+		where = append(where, "topics @> ARRAY["+arg(strings.ToLower(params.Topic))+"::text]")
+	}
+	condition := strings.Join(where, " AND ")
+
+	var totalItems int
+	if err := r.pool.QueryRow(ctx, `SELECT count(*) FROM hadiths WHERE `+condition, args...).Scan(&totalItems); err != nil {
+		return nil, 0, fmt.Errorf("failed to count hadiths of narrator %s: %w", narrator, err)
+	}
+
+	query := `SELECT data FROM hadiths WHERE ` + condition + ` ORDER BY position`
+	if params.Page > 0 && params.Limit > 0 {
+		query += ` LIMIT ` + arg(params.Limit) + ` OFFSET ` + arg((params.Page-1)*params.Limit)
+	}
+
+	hadiths, err := r.queryHadiths(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get hadiths of narrator %s: %w", narrator, err)
+	}
+
+	return hadiths, totalItems, nil
+}
+
+// GetHadithByNumber returns a specific hadith by narrator and number
+func (r *PostgresRepository) GetHadithByNumber(narrator string, number models.Number) (*models.Hadith, error) {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	if err := r.checkNarrator(ctx, narrator); err != nil {
+		return nil, err
+	}
+
+	hadiths, err := r.queryHadiths(ctx, `SELECT data FROM hadiths WHERE narrator = $1 AND number_value = $2 AND number_suffix = $3`,
+		narrator, number.Value, number.Suffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hadith %s:%s: %w", narrator, number, err)
+	}
+	if len(hadiths) == 0 {
+		return nil, fmt.Errorf("hadith number %s not found for narrator %s", number, narrator)
+	}
+
+	return &hadiths[0], nil
+}
+
+// GetHadithRange returns the hadiths of a narrator numbered from..to inclusive, in collection order
+func (r *PostgresRepository) GetHadithRange(narrator string, from, to models.Number) ([]models.Hadith, error) {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	if err := r.checkNarrator(ctx, narrator); err != nil {
+		return nil, err
+	}
+
+	hadiths, err := r.queryHadiths(ctx, `SELECT data FROM hadiths
+		WHERE narrator = $1 AND (number_value, number_suffix) >= ($2, $3) AND (number_value, number_suffix) <= ($4, $5)
+		ORDER BY position`, narrator, from.Value, from.Suffix, to.Value, to.Suffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hadiths %s-%s of narrator %s: %w", from, to, narrator, err)
+	}
+
+	return hadiths, nil
+}
+
+// GetCollection returns metadata for a narrator's collection, with the grade
+// summary counted in the database
+func (r *PostgresRepository) GetCollection(narrator string) (*models.Collection, error) {
+	ctx, cancel := r.queryContext()
+	defer cancel()
+
+	var total int
+	err := r.pool.QueryRow(ctx, `SELECT total_hadiths FROM narrators WHERE slug = $1`, narrator).Scan(&total)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("narrator %s not found", narrator)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection %s: %w", narrator, err)
+	}
+
+	// A hadith graded differently by several graders is counted once under each distinct grade
+	rows, err := r.pool.Query(ctx, `SELECT COALESCE(grade, $2), count(*) FROM hadiths
+		LEFT JOIN LATERAL unnest(grades) AS grade ON true
+		WHERE narrator = $1 GROUP BY 1`, narrator, models.GradeUngraded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count grades of %s: %w", narrator, err)
+	}
+	defer rows.Close()
+
+	grades := make(map[string]int)
+	for rows.Next() {
+		var grade string
+		var count int
+		if err := rows.Scan(&grade, &count); err != nil {
+			return nil, err
+		}
+		grades[grade] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count grades of %s: %w", narrator, err)
+	}
+
+	return r.newCollection(narrator, total, grades)
+}
+
+// GetHadithByScheme returns a specific hadith by narrator and its number in
+// the given scheme, looked up in the database
+func (r *PostgresRepository) GetHadithByScheme(narrator, scheme string, number models.Number) (*models.Hadith, error) {
+	return r.hadithByScheme(r.GetHadithByNumber, narrator, scheme, number)
+}
+
+// GetConcordance returns the numbers a hadith carries in every known scheme,
+// with the hadith looked up in the database
+func (r *PostgresRepository) GetConcordance(narrator, scheme string, number models.Number, target string) (*models.Concordance, error) {
+	return r.concordance(r.GetHadithByNumber, narrator, scheme, number, target)
+}
+
+// checkNarrator returns an error when the narrator is not in the database
+func (r *PostgresRepository) checkNarrator(ctx context.Context, narrator string) error {
+	var exists bool
+	if err := r.pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM narrators WHERE slug = $1)`, narrator).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to look up narrator %s: %w", narrator, err)
+	}
+	if !exists {
+		return fmt.Errorf("narrator %s not found", narrator)
+	}
+	return nil
+}
+
+// queryHadiths decodes the hadiths of a query selecting the data column
+func (r *PostgresRepository) queryHadiths(ctx context.Context, query string, args ...interface{}) ([]models.Hadith, error) {
+	return queryPostgresHadiths(ctx, r.pool, query, args...)
+}
+
+// queryContext returns the context of a query of the source, limited to postgresQueryTimeout
+func (s postgresSource) queryContext() (context.Context, context.CancelFunc) {
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, postgresQueryTimeout)
+}
+
+// narrators lists the narrators in the database
+func (s postgresSource) narrators() ([]string, error) {
+	ctx, cancel := s.queryContext()
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx, `SELECT slug FROM narrators ORDER BY slug`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get narrators: %w", err)
+	}
+	narrators, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get narrators: %w", err)
+	}
+	return narrators, nil
+}
+
+// hadiths reads every hadith of a narrator in collection order
+func (s postgresSource) hadiths(narrator string) ([]models.Hadith, error) {
+	ctx, cancel := s.queryContext()
+	defer cancel()
+
+	hadiths, err := queryPostgresHadiths(ctx, s.pool, `SELECT data FROM hadiths WHERE narrator = $1 ORDER BY position`, narrator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hadiths of narrator %s: %w", narrator, err)
+	}
+	if len(hadiths) == 0 {
+		return nil, fmt.Errorf("narrator %s not found", narrator)
+	}
+	return hadiths, nil
+}
+
+// queryPostgresHadiths decodes the JSON data column of each row
+func queryPostgresHadiths(ctx context.Context, pool *pgxpool.Pool, query string, args ...interface{}) ([]models.Hadith, error) {
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	hadiths, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Hadith, error) {
+		var data []byte
+		var h models.Hadith
+		if err := row.Scan(&data); err != nil {
+			return h, err
+		}
+		return h, json.Unmarshal(data, &h)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if hadiths == nil {
+		return []models.Hadith{}, nil
+	}
+	return hadiths, nil
+}
+
+// gradeList returns the distinct normalized grades of a hadith
+func gradeList(h models.Hadith) []string {
+	grades := []string{}
+	for _, g := range h.Grades {
+		grade := models.NormalizeGrade(g.Grade)
+		if !containsString(grades, grade) {
+			grades = append(grades, grade)
+		}
+	}
+	return grades
+}
+
+// topicList returns the lowercased topics a hadith is tagged with
+func topicList(h models.Hadith) []string {
+	topics := []string{}
+	for _, t := range h.Tags {
+		topic := strings.ToLower(t.Topic)
+		if !containsString(topics, topic) {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+// containsString reports whether list holds s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// tsQuery turns a search query into a tsquery matching each of its words as
+// a prefix. Words are normalized as the search column is.
+func tsQuery(query string) string {
+	var terms []string
+	for _, token := range normalize.Tokenize(query) {
+		terms = append(terms, token+":*")
+	}
+	return strings.Join(terms, " & ")
+}
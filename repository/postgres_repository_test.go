@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hadith-api/normalize"
+)
+
+// newPostgresFixture migrates the database named by TEST_DATABASE_URL and
+// imports the fixture collections into it, replacing its hadiths. The test is
+// skipped when the variable is unset.
+func newPostgresFixture(t *testing.T) (*PostgresRepository, *FileRepository) {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+
+	// A second run finds every migration applied
+	var versions []int
+	for run := 1; run <= 2; run++ {
+		version, err := MigratePostgres(ctx, url)
+		if err != nil {
+			t.Fatalf("MigratePostgres run %d: %v", run, err)
+		}
+		versions = append(versions, version)
+	}
+	if versions[0] != len(postgresMigrations) || versions[1] != len(postgresMigrations) {
+		t.Fatalf("schema versions = %v, want %d twice", versions, len(postgresMigrations))
+	}
+
+	repo, err := NewPostgresRepository(ctx, fixtureDir, url)
+	if err != nil {
+		t.Fatalf("NewPostgresRepository: %v", err)
+	}
+	t.Cleanup(repo.Close)
+
+	total, err := repo.Import(ctx, NewFileRepository(fixtureDir))
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if total != 9 {
+		t.Fatalf("Import imported %d hadiths, want 9", total)
+	}
+
+	return repo, NewFileRepository(fixtureDir)
+}
+
+func TestPostgresRepository(t *testing.T) {
+	repo, files := newPostgresFixture(t)
+
+	t.Run("GetHadithsByNarrator", func(t *testing.T) { checkGetHadithsByNarrator(t, repo, files) })
+	t.Run("GetHadithByNumber", func(t *testing.T) { checkGetHadithByNumber(t, repo, files) })
+	t.Run("GetHadithRange", func(t *testing.T) { checkGetHadithRange(t, repo, files) })
+	t.Run("GetCollection", func(t *testing.T) { checkGetCollection(t, repo, files) })
+	t.Run("KeepsNoCollections", func(t *testing.T) { checkKeepsNoCollections(t, repo, repo.FileRepository, files) })
+}
+
+func TestPostgresHadithNormalize(t *testing.T) {
+	repo, _ := newPostgresFixture(t)
+	ctx := context.Background()
+
+	tests := []string{
+		"إِنَّمَا الْأَعْمَالُ بِالنِّيَّاتِ",
+		"أَنَّ رَسُولَ اللَّهِ قَالَ",
+		"إسلام آمين ٱلرحمن مستشفى الصلاة مؤمن سئل",
+		"الصلاةُ ـــ الصلوة",
+		"Abdullah bin Mas'ud, Mas`ud dan Mas’ud",
+		"Hadits no. ١٢٣ dan ۴۵",
+		"SHALAT Malam",
+	}
+
+	for _, input := range tests {
+		var normalized string
+		if err := repo.pool.QueryRow(ctx, `SELECT hadith_normalize($1)`, input).Scan(&normalized); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := normalize.Tokenize(normalized), normalize.Tokenize(input); !reflect.DeepEqual(got, want) {
+			t.Errorf("hadith_normalize(%q) = %q, tokenized %q, want %q", input, normalized, got, want)
+		}
+
+		// The search column indexes exactly the words queries are tokenized into
+		var lexemes []string
+		if err := repo.pool.QueryRow(ctx, `SELECT COALESCE(array_agg(lexeme), '{}')
+			FROM unnest(to_tsvector('simple', hadith_normalize($1)))`, input).Scan(&lexemes); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := sortedSet(lexemes), sortedSet(normalize.Tokenize(input)); !reflect.DeepEqual(got, want) {
+			t.Errorf("indexed words of %q = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// sortedSet returns the distinct strings of words in order
+func sortedSet(words []string) []string {
+	set := []string{}
+	for _, w := range words {
+		if !containsString(set, w) {
+			set = append(set, w)
+		}
+	}
+	sort.Strings(set)
+	return set
+}
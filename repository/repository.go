@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hadith-api/config"
+	"github.com/hadith-api/models"
+)
+
+// Storage backends selectable with config.Config.Storage
+const (
+	StorageFile     = "file"
+	StorageSQLite   = "sqlite"
+	StoragePostgres = "postgres"
+)
+
+// Repository provides the hadiths and metadata the API serves. FileRepository
+// reads them from the JSON files of the data directory; the database backends
+// hold the hadiths in a database and read the metadata from the files.
+type Repository interface {
+	GetAvailableNarrators() ([]string, error)
+	GetHadithsByNarrator(narrator string, params models.QueryParams) ([]models.Hadith, int, error)
+	GetHadithByNumber(narrator string, number models.Number) (*models.Hadith, error)
+	GetHadithRange(narrator string, from, to models.Number) ([]models.Hadith, error)
+	GetHadithByBook(narrator string, book int, number models.Number) (*models.Hadith, error)
+	GetCollection(narrator string) (*models.Collection, error)
+	GetCollectionInfo(narrator string) (*models.Collection, error)
+	GetAliases() (map[string][]string, error)
+	ResolveNarrator(slug string) (string, error)
+
+	ResolveNumber(narrator, scheme string, number models.Number) (models.Number, error)
+	GetHadithByScheme(narrator, scheme string, number models.Number) (*models.Hadith, error)
+	GetConcordance(narrator, scheme string, number models.Number, target string) (*models.Concordance, error)
+
+	GetCorpus() ([]models.HadithReference, uint64, error)
+	GetOccasions() ([]models.Occasion, error)
+	GetTopics() ([]models.Topic, error)
+	GetTopic(slug string) (*models.Topic, error)
+	GetClusters() ([]models.Cluster, error)
+	GetCluster(id int) (*models.Cluster, error)
+	GetStats() (*models.Stats, error)
+	GetCollectionStats(narrator string) (*models.CollectionStats, error)
+
+	GetRevision() (*models.Revision, error)
+	GetChanges(since int) (*models.ChangeSet, error)
+
+	// WithContext returns the repository with its database queries bound to
+	// ctx, e.g. that of a request. The file backend has none and returns itself.
+	WithContext(ctx context.Context) Repository
+}
+
+// Open returns the repository of the storage backend configured in cfg, with
+// the collection metadata read from dataDir
+func Open(cfg *config.Config, dataDir string) (Repository, error) {
+	switch cfg.Storage {
+	case "", StorageFile:
+		return NewFileRepository(dataDir), nil
+	case StorageSQLite:
+		return NewSQLiteRepository(dataDir, cfg.DatabasePath)
+	case StoragePostgres:
+		return NewPostgresRepository(context.Background(), dataDir, cfg.DatabaseURL)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Storage)
+	}
+}
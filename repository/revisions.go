@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hadith-api/models"
+)
+
+// revisionsDir is the subdirectory of the meta directory holding a snapshot per revision
+const revisionsDir = "revisions"
+
+// snapshot records the content hash of every hadith at a revision, by narrator and number
+type snapshot struct {
+	Revision  int                          `json:"revision"`
+	CreatedAt string                       `json:"created_at,omitempty"`
+	Hadiths   map[string]map[string]string `json:"hadiths"`
+}
+
+// revisionHistory holds the recorded snapshots in revision order, the last
+// of them as head, and the unnumbered snapshot of the current data
+type revisionHistory struct {
+	snapshots []*snapshot
+	head      *snapshot
+	current   *snapshot
+	// pending is set when the current data differs from the head snapshot
+	pending bool
+}
+
+// GetRevision returns the last recorded revision, 0 when none is. Changes to
+// the data since are only numbered once they are recorded.
+func (r *FileRepository) GetRevision() (*models.Revision, error) {
+	history, err := r.loadRevisions()
+	if err != nil {
+		return nil, err
+	}
+	revision := history.head.revision()
+	revision.Pending = history.pending
+	return revision, nil
+}
+
+// GetChanges compares the snapshot of a revision with that of the last
+// recorded revision and returns the hadiths added, modified and removed in
+// between. Revision 0 is the empty dataset, so every hadith counts as added.
+// Added and modified hadiths carry their current content.
+func (r *FileRepository) GetChanges(since int) (*models.ChangeSet, error) {
+	history, err := r.loadRevisions()
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := history.find(since)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &models.ChangeSet{
+		Since:    since,
+		Revision: history.head.Revision,
+		Added:    []models.SelectedHadith{},
+		Modified: []models.SelectedHadith{},
+		Removed:  []models.HadithReference{},
+	}
+
+	for _, narrator := range sortedKeys(history.head.Hadiths) {
+		// A collection removed since the head revision has no content to serve
+		if _, ok := history.current.Hadiths[narrator]; !ok {
+			continue
+		}
+		hadiths, err := r.loadNarratorData(narrator)
+		if err != nil {
+			return nil, err
+		}
+
+		before, after := base.Hadiths[narrator], history.head.Hadiths[narrator]
+		for i := range hadiths {
+			h := &hadiths[i]
+			hash, recorded := after[h.Number.String()]
+			if !recorded {
+				continue
+			}
+			previous, existed := before[h.Number.String()]
+			switch {
+			case !existed:
+				changes.Added = append(changes.Added, models.SelectedHadith{Slug: narrator, Hadith: h})
+			case previous != hash:
+				changes.Modified = append(changes.Modified, models.SelectedHadith{Slug: narrator, Hadith: h})
+			}
+		}
+	}
+
+	for _, narrator := range sortedKeys(base.Hadiths) {
+		var removed []models.Number
+		for number := range base.Hadiths[narrator] {
+			if _, ok := history.head.Hadiths[narrator][number]; ok {
+				continue
+			}
+			parsed, err := models.ParseNumber(number)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q in revision %d: %w", number, base.Revision, err)
+			}
+			removed = append(removed, parsed)
+		}
+
+		sort.Slice(removed, func(i, j int) bool { return removed[i].Less(removed[j]) })
+		for _, number := range removed {
+			changes.Removed = append(changes.Removed, models.HadithReference{Slug: narrator, Number: number})
+		}
+	}
+
+	return changes, nil
+}
+
+// RecordRevision writes the current data as a new snapshot when it differs
+// from the last recorded one. It returns the recorded revision, or nil when
+// the data is unchanged.
+func (r *FileRepository) RecordRevision() (*models.Revision, error) {
+	history, err := r.loadRevisions()
+	if err != nil {
+		return nil, err
+	}
+	if !history.pending {
+		return nil, nil
+	}
+
+	head := &snapshot{
+		Revision:  history.head.Revision + 1,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Hadiths:   history.current.Hadiths,
+	}
+
+	path := filepath.Join(r.DataDir, metaDir, revisionsDir, fmt.Sprintf("%04d.json", head.Revision))
+	if err := writeSnapshot(path, head); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	history.snapshots = append(history.snapshots, head)
+	history.head = head
+	history.pending = false
+	r.mu.Unlock()
+
+	return head.revision(), nil
+}
+
+// loadRevisions reads the recorded snapshots and hashes the current data. The
+// head is the last recorded snapshot, or the empty revision 0.
+func (r *FileRepository) loadRevisions() (*revisionHistory, error) {
+	r.mu.RLock()
+	history := r.revisions
+	r.mu.RUnlock()
+	if history != nil {
+		return history, nil
+	}
+
+	history = &revisionHistory{}
+
+	dir := filepath.Join(r.DataDir, metaDir, revisionsDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read revisions: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		fileData, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read revision %s: %w", entry.Name(), err)
+		}
+		var s snapshot
+		if err := json.Unmarshal(fileData, &s); err != nil {
+			return nil, fmt.Errorf("failed to parse revision %s: %w", entry.Name(), err)
+		}
+		history.snapshots = append(history.snapshots, &s)
+	}
+	sort.Slice(history.snapshots, func(i, j int) bool {
+		return history.snapshots[i].Revision < history.snapshots[j].Revision
+	})
+
+	current, err := r.currentSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	history.head = emptySnapshot()
+	if n := len(history.snapshots); n > 0 {
+		history.head = history.snapshots[n-1]
+	}
+	history.current = current
+	history.pending = !history.head.equal(current)
+
+	r.mu.Lock()
+	r.revisions = history
+	r.mu.Unlock()
+
+	return history, nil
+}
+
+// currentSnapshot collects the content hashes of the loaded data
+func (r *FileRepository) currentSnapshot() (*snapshot, error) {
+	narrators, err := r.GetAvailableNarrators()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &snapshot{Hadiths: make(map[string]map[string]string, len(narrators))}
+	for _, narrator := range narrators {
+		hadiths, err := r.loadNarratorData(narrator)
+		if err != nil {
+			return nil, err
+		}
+
+		hashes := make(map[string]string, len(hadiths))
+		for _, h := range hadiths {
+			hashes[h.Number.String()] = h.Hash
+		}
+		s.Hadiths[narrator] = hashes
+	}
+
+	return s, nil
+}
+
+// emptySnapshot returns revision 0, the empty dataset
+func emptySnapshot() *snapshot {
+	return &snapshot{Hadiths: map[string]map[string]string{}}
+}
+
+// find returns the snapshot of a recorded revision or of revision 0
+func (h *revisionHistory) find(revision int) (*snapshot, error) {
+	if revision == 0 {
+		return emptySnapshot(), nil
+	}
+	for _, s := range h.snapshots {
+		if s.Revision == revision {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("revision %d not found", revision)
+}
+
+// equal reports whether two snapshots hold the same hadiths with the same hashes
+func (s *snapshot) equal(other *snapshot) bool {
+	if len(s.Hadiths) != len(other.Hadiths) {
+		return false
+	}
+	for narrator, hashes := range s.Hadiths {
+		otherHashes, ok := other.Hadiths[narrator]
+		if !ok || len(hashes) != len(otherHashes) {
+			return false
+		}
+		for number, hash := range hashes {
+			if otherHashes[number] != hash {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// revision describes the snapshot as a revision
+func (s *snapshot) revision() *models.Revision {
+	total := 0
+	for _, hashes := range s.Hadiths {
+		total += len(hashes)
+	}
+	return &models.Revision{
+		Revision:     s.Revision,
+		CreatedAt:    s.CreatedAt,
+		TotalHadiths: total,
+	}
+}
+
+// writeSnapshot writes a snapshot with one narrator's hashes per line
+func writeSnapshot(path string, s *snapshot) error {
+	header, err := json.Marshal(struct {
+		Revision  int    `json:"revision"`
+		CreatedAt string `json:"created_at"`
+	}{s.Revision, s.CreatedAt})
+	if err != nil {
+		return fmt.Errorf("failed to encode revision %d: %w", s.Revision, err)
+	}
+
+	// Reuse the header fields and append the hadiths object
+	out := header[:len(header)-1]
+	out = append(out, []byte(",\"hadiths\":{")...)
+	for i, narrator := range sortedKeys(s.Hadiths) {
+		key, _ := json.Marshal(narrator)
+		encoded, err := json.Marshal(s.Hadiths[narrator])
+		if err != nil {
+			return fmt.Errorf("failed to encode revision %d: %w", s.Revision, err)
+		}
+		if i > 0 {
+			out = append(out, ',')
+		}
+		out = append(out, '\n')
+		out = append(out, key...)
+		out = append(out, ':')
+		out = append(out, encoded...)
+	}
+	out = append(out, []byte("\n}}\n")...)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		return fmt.Errorf("failed to write revision file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// sortedKeys returns the narrators of a snapshot in order
+func sortedKeys(m map[string]map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
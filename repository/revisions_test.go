@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// copyFixture copies the fixture data directory, so that a test can change it
+func copyFixture(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	err := filepath.Walk(fixtureDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(fixtureDir, path)
+		target := filepath.Join(dir, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRevisionsOnlyNumberRecordedSnapshots(t *testing.T) {
+	dir := copyFixture(t)
+
+	repo := NewFileRepository(dir)
+	revision, err := repo.GetRevision()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revision.Revision != 0 || !revision.Pending {
+		t.Fatalf("unrecorded data: revision %d, pending %v; want 0, pending", revision.Revision, revision.Pending)
+	}
+	changes, err := repo.GetChanges(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes.Revision != 0 || len(changes.Added) != 0 {
+		t.Errorf("changes before the first revision: revision %d with %d added, want none", changes.Revision, len(changes.Added))
+	}
+
+	recorded, err := repo.RecordRevision()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded == nil || recorded.Revision != 1 || recorded.TotalHadiths != 9 {
+		t.Fatalf("RecordRevision = %+v, want revision 1 with 9 hadiths", recorded)
+	}
+	if again, err := repo.RecordRevision(); err != nil || again != nil {
+		t.Fatalf("recording unchanged data = %+v, %v; want nothing", again, err)
+	}
+
+	// Edit a hadith without recording the change
+	path := filepath.Join(dir, "beta.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.Replace(string(data), "Agama itu nasihat.", "Agama adalah nasihat.", 1)
+	if edited == string(data) {
+		t.Fatal("fixture text to edit not found")
+	}
+	if err := os.WriteFile(path, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo = NewFileRepository(dir)
+	revision, err = repo.GetRevision()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revision.Revision != 1 || !revision.Pending {
+		t.Fatalf("edited data: revision %d, pending %v; want 1, pending", revision.Revision, revision.Pending)
+	}
+	changes, err = repo.GetChanges(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes.Revision != 1 || len(changes.Added)+len(changes.Modified)+len(changes.Removed) != 0 {
+		t.Errorf("unrecorded edit announced: %+v", changes)
+	}
+	if _, err := repo.GetChanges(2); err == nil {
+		t.Error("revision 2 is not recorded: want an error")
+	}
+
+	recorded, err = repo.RecordRevision()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded == nil || recorded.Revision != 2 {
+		t.Fatalf("RecordRevision = %+v, want revision 2", recorded)
+	}
+	changes, err = repo.GetChanges(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes.Modified) != 1 || changes.Modified[0].Slug != "beta" || len(changes.Added) != 0 || len(changes.Removed) != 0 {
+		t.Errorf("changes since 1 = %+v, want the beta edit", changes)
+	}
+
+	// A new repository reads the recorded snapshots back
+	revision, err = NewFileRepository(dir).GetRevision()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revision.Revision != 2 || revision.Pending {
+		t.Errorf("reloaded: revision %d, pending %v; want 2, not pending", revision.Revision, revision.Pending)
+	}
+}
@@ -0,0 +1,478 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/normalize"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteMigrations create and evolve the database schema. Each runs once, in
+// order; the number applied is kept in PRAGMA user_version. The search table
+// is contentless: it only holds the index, keyed by the id of the hadith.
+var sqliteMigrations = []string{
+	`CREATE TABLE narrators (
+		slug          TEXT PRIMARY KEY,
+		total_hadiths INTEGER NOT NULL
+	);
+	CREATE TABLE hadiths (
+		id            INTEGER PRIMARY KEY,
+		narrator      TEXT NOT NULL REFERENCES narrators (slug),
+		position      INTEGER NOT NULL,
+		number_value  INTEGER NOT NULL,
+		number_suffix TEXT NOT NULL,
+		data          TEXT NOT NULL,
+		UNIQUE (narrator, number_value, number_suffix)
+	);
+	CREATE INDEX hadiths_position ON hadiths (narrator, position);
+	CREATE TABLE hadith_grades (
+		hadith_id INTEGER NOT NULL REFERENCES hadiths (id),
+		grade     TEXT NOT NULL,
+		PRIMARY KEY (grade, hadith_id)
+	) WITHOUT ROWID;
+	CREATE TABLE hadith_topics (
+		hadith_id INTEGER NOT NULL REFERENCES hadiths (id),
+		topic     TEXT NOT NULL,
+		PRIMARY KEY (topic, hadith_id)
+	) WITHOUT ROWID;
+	CREATE VIRTUAL TABLE hadith_search USING fts5 (
+		arab,
+		translation,
+		content = '',
+		tokenize = 'unicode61 remove_diacritics 2'
+	);`,
+}
+
+// SQLiteRepository serves the hadiths from an SQLite database imported from
+// the collection files, so that an instance does not hold every collection in
+// memory. Narrators, listing, search, lookups by number and collection
+// metadata query the database. The other operations, which need whole
+// collections, load them from the database through the embedded
+// FileRepository without keeping them in memory. It also reads the meta
+// directory and keeps what is computed from the collections.
+type SQLiteRepository struct {
+	*FileRepository
+	db  *sql.DB
+	ctx context.Context
+}
+
+// sqliteSource reads the collections of the embedded FileRepository from the
+// database, under ctx when it is set
+type sqliteSource struct {
+	db  *sql.DB
+	ctx context.Context
+}
+
+// NewSQLiteRepository opens the database at path, whose schema must be up to
+// date, with the metadata read from dataDir
+func NewSQLiteRepository(dataDir, path string) (*SQLiteRepository, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("database %s not found, create it with the migrate command: %w", path, err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to read schema version of %s: %w", path, err)
+	}
+	if version != len(sqliteMigrations) {
+		db.Close()
+		return nil, fmt.Errorf("database %s has schema version %d instead of %d, run the migrate command",
+			path, version, len(sqliteMigrations))
+	}
+
+	return &SQLiteRepository{
+		FileRepository: newFileRepository(dataDir, sqliteSource{db: db}),
+		db:             db,
+	}, nil
+}
+
+// MigrateSQLite creates the database at path if needed and applies the
+// migrations it has not had yet. It returns the schema version.
+func MigrateSQLite(path string) (int, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	defer db.Close()
+
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version of %s: %w", path, err)
+	}
+	if version > len(sqliteMigrations) {
+		return 0, fmt.Errorf("database %s has schema version %d, newer than this build", path, version)
+	}
+
+	for ; version < len(sqliteMigrations); version++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec(sqliteMigrations[version]); err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("failed to apply migration %d: %w", version+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, version+1)); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		if err := tx.Commit(); err != nil {
+			return 0, err
+		}
+	}
+
+	return version, nil
+}
+
+// Close closes the database
+func (r *SQLiteRepository) Close() error {
+	return r.db.Close()
+}
+
+// WithContext returns a repository whose database queries run under ctx,
+// e.g. a request context, so that they stop when it is canceled. This
+// includes those of the operations of the embedded FileRepository.
+func (r *SQLiteRepository) WithContext(ctx context.Context) Repository {
+	bound := *r
+	bound.ctx = ctx
+	bound.FileRepository = r.FileRepository.withSource(sqliteSource{db: r.db, ctx: ctx})
+	return &bound
+}
+
+// queryContext returns the bound context of the queries, or the background one
+func (r *SQLiteRepository) queryContext() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// Import replaces the hadiths in the database with those src serves, with
+// their sidecar tags and content hashes. It returns the number of hadiths imported.
+func (r *SQLiteRepository) Import(src *FileRepository) (int, error) {
+	narrators, err := src.GetAvailableNarrators()
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM hadith_grades; DELETE FROM hadith_topics; DELETE FROM hadiths;
+		DELETE FROM narrators; INSERT INTO hadith_search (hadith_search) VALUES ('delete-all');`); err != nil {
+		return 0, fmt.Errorf("failed to clear database: %w", err)
+	}
+
+	narratorStmt, err := tx.Prepare(`INSERT INTO narrators VALUES (?, ?)`)
+	if err != nil {
+		return 0, err
+	}
+	hadithStmt, err := tx.Prepare(`INSERT INTO hadiths VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, err
+	}
+	gradeStmt, err := tx.Prepare(`INSERT OR IGNORE INTO hadith_grades VALUES (?, ?)`)
+	if err != nil {
+		return 0, err
+	}
+	topicStmt, err := tx.Prepare(`INSERT OR IGNORE INTO hadith_topics VALUES (?, ?)`)
+	if err != nil {
+		return 0, err
+	}
+	searchStmt, err := tx.Prepare(`INSERT INTO hadith_search (rowid, arab, translation) VALUES (?, ?, ?)`)
+	if err != nil {
+		return 0, err
+	}
+
+	var id int64
+	for _, narrator := range narrators {
+		hadiths, err := src.loadNarratorData(narrator)
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err := narratorStmt.Exec(narrator, len(hadiths)); err != nil {
+			return 0, fmt.Errorf("failed to import narrator %s: %w", narrator, err)
+		}
+
+		for position, h := range hadiths {
+			id++
+			data, err := json.Marshal(h)
+			if err != nil {
+				return 0, fmt.Errorf("failed to encode hadith %s:%s: %w", narrator, h.Number, err)
+			}
+			if _, err := hadithStmt.Exec(id, narrator, position+1, h.Number.Value, h.Number.Suffix, string(data)); err != nil {
+				return 0, fmt.Errorf("failed to import hadith %s:%s: %w", narrator, h.Number, err)
+			}
+			for _, g := range h.Grades {
+				if _, err := gradeStmt.Exec(id, models.NormalizeGrade(g.Grade)); err != nil {
+					return 0, fmt.Errorf("failed to import grades of hadith %s:%s: %w", narrator, h.Number, err)
+				}
+			}
+			for _, t := range h.Tags {
+				if _, err := topicStmt.Exec(id, strings.ToLower(t.Topic)); err != nil {
+					return 0, fmt.Errorf("failed to import tags of hadith %s:%s: %w", narrator, h.Number, err)
+				}
+			}
+			if _, err := searchStmt.Exec(id, normalize.SearchText(h.Arab), normalize.SearchText(h.ID)); err != nil {
+				return 0, fmt.Errorf("failed to index hadith %s:%s: %w", narrator, h.Number, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	// Merge the index into a single segment for faster queries
+	if _, err := r.db.Exec(`INSERT INTO hadith_search (hadith_search) VALUES ('optimize')`); err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// GetHadithsByNarrator returns a narrator's hadiths in collection order,
+// filtered and paginated in the database. The query matches words by prefix
+// through the full-text index, in either the Arabic text or the translation.
+func (r *SQLiteRepository) GetHadithsByNarrator(narrator string, params models.QueryParams) ([]models.Hadith, int, error) {
+	if err := r.checkNarrator(narrator); err != nil {
+		return nil, 0, err
+	}
+
+	where := []string{"h.narrator = ?"}
+	args := []interface{}{narrator}
+
+	if params.Query != "" {
+		match := ftsQuery(params.Query)
+		if match == "" {
+			return []models.Hadith{}, 0, nil
+		}
+		where = append(where, "h.id IN (SELECT rowid FROM hadith_search WHERE hadith_search MATCH ?)")
+		args = append(args, match)
+	}
+	if params.Grade != "" {
+		if grade := models.NormalizeGrade(params.Grade); grade == models.GradeUngraded {
+			where = append(where, "NOT EXISTS (SELECT 1 FROM hadith_grades g WHERE g.hadith_id = h.id)")
+		} else {
+			where = append(where, "EXISTS (SELECT 1 FROM hadith_grades g WHERE g.hadith_id = h.id AND g.grade = ?)")
+			args = append(args, grade)
+		}
+	}
+	if params.Topic != "" {
+		where = append(where, "EXISTS (SELECT 1 FROM hadith_topics t WHERE t.hadith_id = h.id AND t.topic = ?)")
+		args = append(args, strings.ToLower(params.Topic))
+	}
+	condition := strings.Join(where, " AND ")
+
+	var totalItems int
+	if err := r.db.QueryRowContext(r.queryContext(), `SELECT COUNT(*) FROM hadiths h WHERE `+condition, args...).Scan(&totalItems); err != nil {
+		return nil, 0, fmt.Errorf("failed to count hadiths of narrator %s: %w", narrator, err)
+	}
+
+	query := `SELECT h.data FROM hadiths h WHERE ` + condition + ` ORDER BY h.position`
+	if params.Page > 0 && params.Limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, params.Limit, (params.Page-1)*params.Limit)
+	}
+
+	hadiths, err := r.queryHadiths(query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get hadiths of narrator %s: %w", narrator, err)
+	}
+
+	return hadiths, totalItems, nil
+}
+
+// GetHadithByNumber returns a specific hadith by narrator and number
+func (r *SQLiteRepository) GetHadithByNumber(narrator string, number models.Number) (*models.Hadith, error) {
+	if err := r.checkNarrator(narrator); err != nil {
+		return nil, err
+	}
+
+	hadiths, err := r.queryHadiths(`SELECT data FROM hadiths WHERE narrator = ? AND number_value = ? AND number_suffix = ?`,
+		narrator, number.Value, number.Suffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hadith %s:%s: %w", narrator, number, err)
+	}
+	if len(hadiths) == 0 {
+		return nil, fmt.Errorf("hadith number %s not found for narrator %s", number, narrator)
+	}
+
+	return &hadiths[0], nil
+}
+
+// GetHadithRange returns the hadiths of a narrator numbered from..to inclusive, in collection order
+func (r *SQLiteRepository) GetHadithRange(narrator string, from, to models.Number) ([]models.Hadith, error) {
+	if err := r.checkNarrator(narrator); err != nil {
+		return nil, err
+	}
+
+	hadiths, err := r.queryHadiths(`SELECT data FROM hadiths
+		WHERE narrator = ? AND (number_value, number_suffix) >= (?, ?) AND (number_value, number_suffix) <= (?, ?)
+		ORDER BY position`, narrator, from.Value, from.Suffix, to.Value, to.Suffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hadiths %s-%s of narrator %s: %w", from, to, narrator, err)
+	}
+
+	return hadiths, nil
+}
+
+// GetCollection returns metadata for a narrator's collection, with the grade
+// summary counted in the database
+func (r *SQLiteRepository) GetCollection(narrator string) (*models.Collection, error) {
+	var total int
+	err := r.db.QueryRowContext(r.queryContext(), `SELECT total_hadiths FROM narrators WHERE slug = ?`, narrator).Scan(&total)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("narrator %s not found", narrator)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection %s: %w", narrator, err)
+	}
+
+	grades := make(map[string]int)
+	rows, err := r.db.QueryContext(r.queryContext(), `SELECT g.grade, COUNT(*) FROM hadith_grades g
+		JOIN hadiths h ON h.id = g.hadith_id WHERE h.narrator = ? GROUP BY g.grade`, narrator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count grades of %s: %w", narrator, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var grade string
+		var count int
+		if err := rows.Scan(&grade, &count); err != nil {
+			return nil, err
+		}
+		grades[grade] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var ungraded int
+	if err := r.db.QueryRowContext(r.queryContext(), `SELECT COUNT(*) FROM hadiths h WHERE h.narrator = ?
+		AND NOT EXISTS (SELECT 1 FROM hadith_grades g WHERE g.hadith_id = h.id)`, narrator).Scan(&ungraded); err != nil {
+		return nil, fmt.Errorf("failed to count grades of %s: %w", narrator, err)
+	}
+	if ungraded > 0 {
+		grades[models.GradeUngraded] = ungraded
+	}
+
+	return r.newCollection(narrator, total, grades)
+}
+
+// GetHadithByScheme returns a specific hadith by narrator and its number in
+// the given scheme, looked up in the database
+func (r *SQLiteRepository) GetHadithByScheme(narrator, scheme string, number models.Number) (*models.Hadith, error) {
+	return r.hadithByScheme(r.GetHadithByNumber, narrator, scheme, number)
+}
+
+// GetConcordance returns the numbers a hadith carries in every known scheme,
+// with the hadith looked up in the database
+func (r *SQLiteRepository) GetConcordance(narrator, scheme string, number models.Number, target string) (*models.Concordance, error) {
+	return r.concordance(r.GetHadithByNumber, narrator, scheme, number, target)
+}
+
+// checkNarrator returns an error when the narrator is not in the database
+func (r *SQLiteRepository) checkNarrator(narrator string) error {
+	var exists bool
+	if err := r.db.QueryRowContext(r.queryContext(), `SELECT EXISTS (SELECT 1 FROM narrators WHERE slug = ?)`, narrator).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to look up narrator %s: %w", narrator, err)
+	}
+	if !exists {
+		return fmt.Errorf("narrator %s not found", narrator)
+	}
+	return nil
+}
+
+// queryHadiths decodes the hadiths of a query selecting the data column
+func (r *SQLiteRepository) queryHadiths(query string, args ...interface{}) ([]models.Hadith, error) {
+	return scanHadiths(r.db.QueryContext(r.queryContext(), query, args...))
+}
+
+// queryContext returns the context of the source's queries
+func (s sqliteSource) queryContext() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+// narrators lists the narrators in the database
+func (s sqliteSource) narrators() ([]string, error) {
+	rows, err := s.db.QueryContext(s.queryContext(), `SELECT slug FROM narrators ORDER BY slug`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get narrators: %w", err)
+	}
+	defer rows.Close()
+
+	var narrators []string
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, err
+		}
+		narrators = append(narrators, slug)
+	}
+	return narrators, rows.Err()
+}
+
+// hadiths reads every hadith of a narrator in collection order
+func (s sqliteSource) hadiths(narrator string) ([]models.Hadith, error) {
+	hadiths, err := scanHadiths(s.db.QueryContext(s.queryContext(), `SELECT data FROM hadiths WHERE narrator = ? ORDER BY position`, narrator))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hadiths of narrator %s: %w", narrator, err)
+	}
+	if len(hadiths) == 0 {
+		return nil, fmt.Errorf("narrator %s not found", narrator)
+	}
+	return hadiths, nil
+}
+
+// scanHadiths decodes the JSON data column of each row
+func scanHadiths(rows *sql.Rows, err error) ([]models.Hadith, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hadiths := []models.Hadith{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var h models.Hadith
+		if err := json.Unmarshal([]byte(data), &h); err != nil {
+			return nil, err
+		}
+		hadiths = append(hadiths, h)
+	}
+	return hadiths, rows.Err()
+}
+
+// ftsQuery turns a search query into an FTS5 query matching each of its words
+// as a prefix. Words are tokenized as the index is, with normalize.SearchText.
+func ftsQuery(query string) string {
+	var terms []string
+	for _, token := range normalize.Tokenize(query) {
+		terms = append(terms, `"`+token+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
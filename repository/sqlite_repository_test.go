@@ -0,0 +1,374 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hadith-api/models"
+)
+
+// fixtureDir holds two small collections with grades, sidecar tags, a sub-numbered hadith and apostrophes
+const fixtureDir = "testdata/data"
+
+// newSQLiteFixture migrates a temporary database and imports the fixture collections into it
+func newSQLiteFixture(t *testing.T) (*SQLiteRepository, *FileRepository) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "hadith.db")
+	if _, err := MigrateSQLite(path); err != nil {
+		t.Fatalf("MigrateSQLite: %v", err)
+	}
+
+	repo, err := NewSQLiteRepository(fixtureDir, path)
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	files := NewFileRepository(fixtureDir)
+	total, err := repo.Import(files)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if total != 9 {
+		t.Fatalf("Import imported %d hadiths, want 9", total)
+	}
+
+	return repo, NewFileRepository(fixtureDir)
+}
+
+// narratorQueries are the listings the database backends must answer like
+// FileRepository. The queries are those for which substring and word prefix
+// matching agree.
+var narratorQueries = []struct {
+	name     string
+	narrator string
+	params   models.QueryParams
+}{
+	{"all", "alpha", models.QueryParams{}},
+	{"first page", "alpha", models.QueryParams{Page: 1, Limit: 2}},
+	{"last page", "alpha", models.QueryParams{Page: 3, Limit: 2}},
+	{"page past the end", "alpha", models.QueryParams{Page: 5, Limit: 2}},
+	{"query", "alpha", models.QueryParams{Query: "shalat"}},
+	{"query with apostrophe", "beta", models.QueryParams{Query: "Mas'ud"}},
+	{"arabic query", "alpha", models.QueryParams{Query: "الْأَعْمَالُ"}},
+	{"query without match", "alpha", models.QueryParams{Query: "zakat"}},
+	{"grade", "alpha", models.QueryParams{Grade: "sahih"}},
+	{"grade alias", "alpha", models.QueryParams{Grade: "hassan"}},
+	{"ungraded", "alpha", models.QueryParams{Grade: "ungraded"}},
+	{"sidecar topic", "alpha", models.QueryParams{Topic: "prayer"}},
+	{"inline topic", "alpha", models.QueryParams{Topic: "Fasting"}},
+	{"combined", "alpha", models.QueryParams{Topic: "prayer", Grade: "daif", Page: 1, Limit: 10}},
+}
+
+func mustNumber(t *testing.T, s string) models.Number {
+	t.Helper()
+	n, err := models.ParseNumber(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestSQLiteRepositoryGetHadithsByNarrator(t *testing.T) {
+	repo, files := newSQLiteFixture(t)
+	checkGetHadithsByNarrator(t, repo, files)
+}
+
+// checkGetHadithsByNarrator compares the listings of a database backend with
+// those of FileRepository on the same data
+func checkGetHadithsByNarrator(t *testing.T, repo Repository, files *FileRepository) {
+	t.Helper()
+
+	for _, tt := range narratorQueries {
+		t.Run(tt.name, func(t *testing.T) {
+			want, wantTotal, err := files.GetHadithsByNarrator(tt.narrator, tt.params)
+			if err != nil {
+				t.Fatalf("FileRepository: %v", err)
+			}
+			got, gotTotal, err := repo.GetHadithsByNarrator(tt.narrator, tt.params)
+			if err != nil {
+				t.Fatalf("database: %v", err)
+			}
+
+			if gotTotal != wantTotal {
+				t.Errorf("total = %d, want %d", gotTotal, wantTotal)
+			}
+			if len(got) != len(want) || (len(want) > 0 && !reflect.DeepEqual(got, want)) {
+				t.Errorf("hadiths = %v, want %v", got, want)
+			}
+		})
+	}
+
+	if _, _, err := repo.GetHadithsByNarrator("gamma", models.QueryParams{}); err == nil {
+		t.Error("unknown narrator: want an error")
+	}
+}
+
+func TestSQLiteRepositoryGetHadithByNumber(t *testing.T) {
+	repo, files := newSQLiteFixture(t)
+	checkGetHadithByNumber(t, repo, files)
+}
+
+// checkGetHadithByNumber compares lookups by number, sub-numbers included
+func checkGetHadithByNumber(t *testing.T, repo Repository, files *FileRepository) {
+	t.Helper()
+
+	for _, number := range []string{"1", "2", "2a", "5"} {
+		want, err := files.GetHadithByNumber("alpha", mustNumber(t, number))
+		if err != nil {
+			t.Fatalf("FileRepository %s: %v", number, err)
+		}
+		got, err := repo.GetHadithByNumber("alpha", mustNumber(t, number))
+		if err != nil {
+			t.Fatalf("database %s: %v", number, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("hadith %s = %+v, want %+v", number, got, want)
+		}
+	}
+
+	if _, err := repo.GetHadithByNumber("alpha", mustNumber(t, "6")); err == nil {
+		t.Error("missing number: want an error")
+	}
+	if _, err := repo.GetHadithByNumber("gamma", mustNumber(t, "1")); err == nil {
+		t.Error("unknown narrator: want an error")
+	}
+}
+
+func TestSQLiteRepositoryGetHadithRange(t *testing.T) {
+	repo, files := newSQLiteFixture(t)
+	checkGetHadithRange(t, repo, files)
+}
+
+// checkGetHadithRange compares ranges, which take in the sub-numbers between their ends
+func checkGetHadithRange(t *testing.T, repo Repository, files *FileRepository) {
+	t.Helper()
+
+	tests := []struct{ from, to string }{
+		{"1", "5"},
+		{"2", "3"},
+		{"2a", "2a"},
+		{"2b", "4"},
+		{"6", "9"},
+	}
+
+	for _, tt := range tests {
+		from, to := mustNumber(t, tt.from), mustNumber(t, tt.to)
+		want, err := files.GetHadithRange("alpha", from, to)
+		if err != nil {
+			t.Fatalf("FileRepository %s-%s: %v", tt.from, tt.to, err)
+		}
+		got, err := repo.GetHadithRange("alpha", from, to)
+		if err != nil {
+			t.Fatalf("database %s-%s: %v", tt.from, tt.to, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("range %s-%s = %v, want %v", tt.from, tt.to, got, want)
+		}
+	}
+}
+
+func TestSQLiteRepositoryGetCollection(t *testing.T) {
+	repo, files := newSQLiteFixture(t)
+	checkGetCollection(t, repo, files)
+}
+
+// checkGetCollection compares the collection metadata and grade summaries
+func checkGetCollection(t *testing.T, repo Repository, files *FileRepository) {
+	t.Helper()
+
+	for _, narrator := range []string{"alpha", "beta"} {
+		want, err := files.GetCollection(narrator)
+		if err != nil {
+			t.Fatalf("FileRepository %s: %v", narrator, err)
+		}
+		got, err := repo.GetCollection(narrator)
+		if err != nil {
+			t.Fatalf("database %s: %v", narrator, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("collection %s = %+v, want %+v", narrator, got, want)
+		}
+	}
+
+	narrators, err := repo.GetAvailableNarrators()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(narrators, []string{"alpha", "beta"}) {
+		t.Errorf("narrators = %v", narrators)
+	}
+}
+
+func TestSQLiteRepositoryWithContext(t *testing.T) {
+	repo, _ := newSQLiteFixture(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bound := repo.WithContext(ctx)
+	if _, err := bound.GetHadithByNumber("alpha", mustNumber(t, "1")); err != nil {
+		t.Fatalf("live context: %v", err)
+	}
+
+	cancel()
+	if _, _, err := bound.GetHadithsByNarrator("alpha", models.QueryParams{}); err == nil {
+		t.Error("canceled context: want an error")
+	}
+	// The operations of the embedded FileRepository query under the context too
+	if _, err := bound.GetStats(); err == nil {
+		t.Error("canceled context, statistics: want an error")
+	}
+	if _, err := repo.GetHadithByNumber("alpha", mustNumber(t, "1")); err != nil {
+		t.Errorf("unbound repository after cancel: %v", err)
+	}
+	if _, err := repo.GetStats(); err != nil {
+		t.Errorf("unbound repository after cancel, statistics: %v", err)
+	}
+}
+
+// countingSource counts the whole collections read from a source
+type countingSource struct {
+	collectionSource
+	loads *int
+}
+
+func (s countingSource) hadiths(narrator string) ([]models.Hadith, error) {
+	*s.loads++
+	return s.collectionSource.hadiths(narrator)
+}
+
+func TestSQLiteRepositoryKeepsNoCollections(t *testing.T) {
+	repo, files := newSQLiteFixture(t)
+	checkKeepsNoCollections(t, repo, repo.FileRepository, files)
+}
+
+// checkKeepsNoCollections checks that a database repository looks hadiths up
+// by scheme in the database, and that neither those lookups nor the
+// operations over whole collections keep collections in embedded
+func checkKeepsNoCollections(t *testing.T, repo Repository, embedded, files *FileRepository) {
+	t.Helper()
+
+	var loads int
+	embedded.source = countingSource{embedded.source, &loads}
+	bound := repo.WithContext(context.Background())
+
+	for _, r := range []Repository{repo, bound} {
+		got, err := r.GetHadithByScheme("alpha", "fuad", mustNumber(t, "12b"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := files.GetHadithByScheme("alpha", "fuad", mustNumber(t, "12b"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GetHadithByScheme = %+v, want %+v", got, want)
+		}
+
+		if _, err := r.GetConcordance("alpha", "print", mustNumber(t, "3"), ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	embedded.mu.RLock()
+	loaded := len(embedded.cache)
+	embedded.mu.RUnlock()
+	if loaded != 0 || loads != 0 {
+		t.Fatalf("lookups by scheme read %d whole collections and kept %d in memory, want none", loads, loaded)
+	}
+
+	// Operations over whole collections read them without keeping them
+	if _, err := bound.GetStats(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := bound.GetCorpus(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bound.GetTopics(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bound.GetChanges(0); err != nil {
+		t.Fatal(err)
+	}
+
+	embedded.mu.RLock()
+	loaded = len(embedded.cache)
+	embedded.mu.RUnlock()
+	if loaded != 0 {
+		t.Errorf("%d collections kept in memory after corpus-wide operations, want none", loaded)
+	}
+}
+
+func TestMigrateSQLiteIsIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hadith.db")
+
+	for run := 1; run <= 2; run++ {
+		version, err := MigrateSQLite(path)
+		if err != nil {
+			t.Fatalf("run %d: %v", run, err)
+		}
+		if version != len(sqliteMigrations) {
+			t.Errorf("run %d: version = %d, want %d", run, version, len(sqliteMigrations))
+		}
+	}
+
+	repo, err := NewSQLiteRepository(fixtureDir, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+
+	// Importing twice replaces the data instead of adding to it
+	for run := 1; run <= 2; run++ {
+		if _, err := repo.Import(NewFileRepository(fixtureDir)); err != nil {
+			t.Fatalf("import %d: %v", run, err)
+		}
+	}
+	_, total, err := repo.GetHadithsByNarrator("alpha", models.QueryParams{Query: "shalat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Errorf("after two imports, total = %d, want 2", total)
+	}
+}
+
+func TestNewSQLiteRepositorySchemaMismatch(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := NewSQLiteRepository(fixtureDir, filepath.Join(dir, "missing.db")); err == nil {
+		t.Error("missing database: want an error")
+	}
+
+	path := filepath.Join(dir, "old.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`CREATE TABLE t (x INTEGER)`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	_, err = NewSQLiteRepository(fixtureDir, path)
+	if err == nil || !strings.Contains(err.Error(), "schema version 0 instead of 1") {
+		t.Errorf("unmigrated database: err = %v, want a schema version mismatch", err)
+	}
+
+	db, err = sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`PRAGMA user_version = 99`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	if _, err := MigrateSQLite(path); err == nil || !strings.Contains(err.Error(), "newer than this build") {
+		t.Errorf("newer database: err = %v, want a refusal to migrate", err)
+	}
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/normalize"
+)
+
+// topWords is the number of most frequent words reported in the statistics
+const topWords = 25
+
+// textCounter accumulates the statistics of one text field over a set of hadiths
+type textCounter struct {
+	hadiths int
+	chars   int
+	words   int
+	longest models.LongestHadith
+	counts  map[string]int
+}
+
+func newTextCounter() *textCounter {
+	return &textCounter{counts: make(map[string]int)}
+}
+
+// add counts the text of a single hadith
+func (t *textCounter) add(slug string, number models.Number, text string) {
+	text = strings.TrimSpace(text)
+	length := utf8.RuneCountInString(text)
+	tokens := normalize.Tokenize(text)
+
+	t.hadiths++
+	t.chars += length
+	t.words += len(tokens)
+	if length > t.longest.Length {
+		t.longest = models.LongestHadith{Slug: slug, Number: number, Length: length}
+	}
+	for _, token := range tokens {
+		t.counts[token]++
+	}
+}
+
+// merge adds the counts of another counter
+func (t *textCounter) merge(other *textCounter) {
+	t.hadiths += other.hadiths
+	t.chars += other.chars
+	t.words += other.words
+	if other.longest.Length > t.longest.Length {
+		t.longest = other.longest
+	}
+	for word, n := range other.counts {
+		t.counts[word] += n
+	}
+}
+
+// stats returns the accumulated statistics
+func (t *textCounter) stats() models.TextStats {
+	stats := models.TextStats{
+		Longest:        t.longest,
+		VocabularySize: len(t.counts),
+		TopWords:       []models.WordCount{},
+	}
+	if t.hadiths > 0 {
+		stats.AverageLength = math.Round(float64(t.chars)/float64(t.hadiths)*100) / 100
+		stats.AverageWords = math.Round(float64(t.words)/float64(t.hadiths)*100) / 100
+	}
+
+	for word, n := range t.counts {
+		if !normalize.IsStopword(word) {
+			stats.TopWords = append(stats.TopWords, models.WordCount{Word: word, Count: n})
+		}
+	}
+	sort.Slice(stats.TopWords, func(i, j int) bool {
+		if stats.TopWords[i].Count != stats.TopWords[j].Count {
+			return stats.TopWords[i].Count > stats.TopWords[j].Count
+		}
+		return stats.TopWords[i].Word < stats.TopWords[j].Word
+	})
+	if len(stats.TopWords) > topWords {
+		stats.TopWords = stats.TopWords[:topWords]
+	}
+
+	return stats
+}
+
+// collectionStats holds a collection's statistics together with the word
+// counts needed to combine them into corpus-wide statistics
+type collectionStats struct {
+	stats       models.CollectionStats
+	arabic      *textCounter
+	translation *textCounter
+}
+
+// computeStats computes the statistics of a collection
+func computeStats(narrator string, hadiths []models.Hadith) *collectionStats {
+	s := &collectionStats{
+		stats:       models.CollectionStats{Slug: narrator, TotalHadiths: len(hadiths), Gaps: []models.NumberRange{}},
+		arabic:      newTextCounter(),
+		translation: newTextCounter(),
+	}
+
+	present := make(map[int]bool)
+	for i, h := range hadiths {
+		if i == 0 || h.Number.Less(s.stats.FirstNumber) {
+			s.stats.FirstNumber = h.Number
+		}
+		if i == 0 || s.stats.LastNumber.Less(h.Number) {
+			s.stats.LastNumber = h.Number
+		}
+		present[h.Number.Value] = true
+
+		s.arabic.add(narrator, h.Number, h.Arab)
+		s.translation.add(narrator, h.Number, h.ID)
+	}
+
+	// Gaps are found on the numeric part, so 12a and 12b both fill 12
+	for n := s.stats.FirstNumber.Value; n <= s.stats.LastNumber.Value && len(hadiths) > 0; n++ {
+		if present[n] {
+			continue
+		}
+		gap := models.NumberRange{From: n, To: n}
+		for gap.To+1 <= s.stats.LastNumber.Value && !present[gap.To+1] {
+			gap.To++
+		}
+		s.stats.Gaps = append(s.stats.Gaps, gap)
+		s.stats.MissingNumbers += gap.To - gap.From + 1
+		n = gap.To
+	}
+
+	s.stats.Arabic = s.arabic.stats()
+	s.stats.Translation = s.translation.stats()
+
+	return s
+}
+
+// collectionStatsOf returns the statistics of a narrator's collection,
+// computing them the first time they are requested
+func (r *FileRepository) collectionStatsOf(narrator string) (*collectionStats, error) {
+	r.mu.RLock()
+	s, ok := r.stats[narrator]
+	r.mu.RUnlock()
+	if ok {
+		return s, nil
+	}
+
+	hadiths, err := r.loadNarratorData(narrator)
+	if err != nil {
+		return nil, err
+	}
+	s = computeStats(narrator, hadiths)
+
+	r.mu.Lock()
+	r.stats[narrator] = s
+	r.mu.Unlock()
+
+	return s, nil
+}
+
+// GetCollectionStats returns the statistics of a narrator's collection
+func (r *FileRepository) GetCollectionStats(narrator string) (*models.CollectionStats, error) {
+	s, err := r.collectionStatsOf(narrator)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := s.stats
+	return &stats, nil
+}
+
+// GetStats returns the statistics of the whole corpus and of each collection
+func (r *FileRepository) GetStats() (*models.Stats, error) {
+	r.mu.RLock()
+	cached := r.corpusStats
+	r.mu.RUnlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	narrators, err := r.GetAvailableNarrators()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(narrators)
+
+	stats := &models.Stats{Collections: []models.CollectionStats{}}
+	arabic, translation := newTextCounter(), newTextCounter()
+	for _, narrator := range narrators {
+		s, err := r.collectionStatsOf(narrator)
+		if err != nil {
+			return nil, err
+		}
+
+		stats.TotalHadiths += s.stats.TotalHadiths
+		stats.Collections = append(stats.Collections, s.stats)
+		arabic.merge(s.arabic)
+		translation.merge(s.translation)
+	}
+	stats.Arabic = arabic.stats()
+	stats.Translation = translation.stats()
+
+	r.mu.Lock()
+	r.corpusStats = stats
+	r.mu.Unlock()
+
+	return stats, nil
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hadith-api/models"
+)
+
+// topicsFile is the on-disk format of the topic taxonomy in meta/topics.json
+type topicsFile struct {
+	Topics []models.Topic `json:"topics"`
+}
+
+// tagFile is the on-disk format of a collection's sidecar tag file in
+// meta/topics/<narrator>.json, mapping hadith numbers onto their tags
+type tagFile struct {
+	Hadiths map[string][]models.Tag `json:"hadiths"`
+}
+
+// GetTopics returns the topic taxonomy with the number of tagged hadiths per topic across all narrators
+func (r *FileRepository) GetTopics() ([]models.Topic, error) {
+	taxonomy, err := r.loadTaxonomy()
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := r.countTopics()
+	if err != nil {
+		return nil, err
+	}
+
+	topics := make([]models.Topic, len(taxonomy))
+	for i, topic := range taxonomy {
+		topic.TotalHadiths = counts[topic.Slug]
+		topics[i] = topic
+	}
+
+	return topics, nil
+}
+
+// GetTopic returns a single topic from the taxonomy, matching its slug
+// case-insensitively. Only the taxonomy is read, so the topic comes without
+// its number of tagged hadiths.
+func (r *FileRepository) GetTopic(slug string) (*models.Topic, error) {
+	taxonomy, err := r.loadTaxonomy()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, topic := range taxonomy {
+		if strings.EqualFold(topic.Slug, slug) {
+			return &topic, nil
+		}
+	}
+
+	return nil, fmt.Errorf("topic %s not found", slug)
+}
+
+// countTopics counts the tagged hadiths per topic across all narrators, once
+func (r *FileRepository) countTopics() (map[string]int, error) {
+	r.mu.RLock()
+	counts := r.topicCounts
+	r.mu.RUnlock()
+	if counts != nil {
+		return counts, nil
+	}
+
+	narrators, err := r.GetAvailableNarrators()
+	if err != nil {
+		return nil, err
+	}
+
+	counts = make(map[string]int)
+	for _, narrator := range narrators {
+		hadiths, err := r.loadNarratorData(narrator)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range hadiths {
+			for _, t := range h.Tags {
+				counts[t.Topic]++
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.topicCounts = counts
+	r.mu.Unlock()
+
+	return counts, nil
+}
+
+// loadTaxonomy loads the topic taxonomy from the meta directory.
+// A missing taxonomy is not an error; there are then no topics.
+func (r *FileRepository) loadTaxonomy() ([]models.Topic, error) {
+	r.mu.RLock()
+	taxonomy := r.taxonomy
+	r.mu.RUnlock()
+	if taxonomy != nil {
+		return taxonomy, nil
+	}
+
+	taxonomy = []models.Topic{}
+
+	filePath := filepath.Join(r.DataDir, metaDir, "topics.json")
+	fileData, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read topic taxonomy: %w", err)
+	}
+
+	if err == nil {
+		var file topicsFile
+		if err := json.Unmarshal(fileData, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse topic taxonomy: %w", err)
+		}
+		taxonomy = append(taxonomy, file.Topics...)
+	}
+
+	r.mu.Lock()
+	r.taxonomy = taxonomy
+	r.mu.Unlock()
+
+	return taxonomy, nil
+}
+
+// applyTags merges the tags from a narrator's sidecar tag file into its hadiths.
+// Tags already present in the data file are kept; a missing sidecar is not an error.
+func (r *FileRepository) applyTags(narrator string, hadiths []models.Hadith) error {
+	filePath := filepath.Join(r.DataDir, metaDir, "topics", fmt.Sprintf("%s.json", narrator))
+	fileData, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read tags for narrator %s: %w", narrator, err)
+	}
+
+	var file tagFile
+	if err := json.Unmarshal(fileData, &file); err != nil {
+		return fmt.Errorf("failed to parse tags for narrator %s: %w", narrator, err)
+	}
+
+	for i := range hadiths {
+		for _, tag := range file.Hadiths[hadiths[i].Number.String()] {
+			if !hadiths[i].HasTopic(tag.Topic) {
+				hadiths[i].Tags = append(hadiths[i].Tags, tag)
+			}
+		}
+	}
+
+	return nil
+}
+
+// WriteTagFile writes tags in the sidecar tag file format, one hadith per line in number order
+func WriteTagFile(path string, tags map[models.Number][]models.Tag) error {
+	numbers := make([]models.Number, 0, len(tags))
+	for number := range tags {
+		numbers = append(numbers, number)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i].Less(numbers[j]) })
+
+	var b strings.Builder
+	b.WriteString("{\n  \"hadiths\": {")
+	for i, number := range numbers {
+		encoded, err := json.Marshal(tags[number])
+		if err != nil {
+			return fmt.Errorf("failed to encode tags for hadith %s: %w", number, err)
+		}
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, "\n    %q: %s", number.String(), encoded)
+	}
+	b.WriteString("\n  }\n}\n")
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write tag file %s: %w", path, err)
+	}
+
+	return nil
+}
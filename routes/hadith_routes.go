@@ -5,14 +5,58 @@ import (
 	"github.com/hadith-api/handlers"
 )
 
-// SetupHadithRoutes configures all hadith-related routes
+// SetupHadithRoutes configures all hadith-related routes.
+// Routes with a narrator slug redirect aliases to the canonical slug first.
 func SetupHadithRoutes(router *gin.RouterGroup, handler *handlers.HadithHandler) {
 	// Get all available narrators
 	router.GET("/narrators", handler.GetNarrators)
+	// Get collection metadata for a narrator
+	router.GET("/narrators/:slug", handler.CanonicalNarrator, handler.GetCollection)
 	// Get all Hadiths with pagination(limit 10 per page) and search optional
 	router.GET("/hadis", handler.GetAllHadiths)
+	// Get a random hadith, optionally filtered
+	router.GET("/hadis/random", handler.GetRandomHadith)
+	// Get the deterministic hadith of the day
+	router.GET("/hadis/daily", handler.GetDailyHadith)
 	// Get hadiths by narrator
-	router.GET("/hadis/:slug", handler.GetHadithsByNarrator)
-	// Get hadith by narrator and number
-	router.GET("/hadis/:slug/:number", handler.GetHadithByNumber)
+	router.GET("/hadis/:slug", handler.CanonicalNarrator, handler.GetHadithsByNarrator)
+	// Get several hadiths across narrators at once
+	router.POST("/hadis/batch", handler.GetHadithBatch)
+	// Get hadith by narrator and number, or a range of numbers such as 1-40
+	router.GET("/hadis/:slug/:number", handler.CanonicalNarrator, handler.GetHadithByNumber)
+	// Translate a hadith number between numbering schemes
+	router.GET("/hadis/:slug/:number/concordance", handler.CanonicalNarrator, handler.GetConcordance)
+	// Get a formatted citation of a hadith
+	router.GET("/hadis/:slug/:number/cite", handler.CanonicalNarrator, handler.GetCitation)
+	// Get the topic taxonomy
+	router.GET("/topics", handler.GetTopics)
+	// Get hadiths tagged with a topic
+	router.GET("/topics/:topic/hadis", handler.GetHadithsByTopic)
+	// Get the thematic clusters
+	router.GET("/clusters", handler.GetClusters)
+	// Get hadiths assigned to a cluster
+	router.GET("/clusters/:id/hadis", handler.GetHadithsByCluster)
+	// Get corpus statistics
+	router.GET("/stats", handler.GetStats)
+	// Get statistics of a narrator's collection
+	router.GET("/stats/:slug", handler.CanonicalNarrator, handler.GetCollectionStats)
+	// Get every occurrence of a term with its context
+	router.GET("/concordance", handler.GetKeywordInContext)
+	// Get frequent n-grams and collocations
+	router.GET("/ngrams", handler.GetNGrams)
+	// Compare two hadiths word by word
+	router.GET("/compare", handler.CompareHadiths)
+	// Export all collections for bulk download
+	router.GET("/export", handler.ExportCorpus)
+	// Export a narrator's collection for bulk download, as a PDF or EPUB booklet
+	// with a .pdf or .epub suffix, or as an Anki deck with an .apkg suffix
+	router.GET("/export/:slug", handler.CanonicalNarrator, handler.ExportCollection)
+	// Export a narrator's collection as TEI XML
+	router.GET("/export/:slug/tei", handler.CanonicalNarrator, handler.ExportTEI)
+	// Download all collections as an SQLite file for offline use
+	router.GET("/bundle", handler.GetBundle)
+	// Get the hadiths changed since a dataset revision
+	router.GET("/changes", handler.GetChanges)
+	// Resolve a free-text citation into hadiths
+	router.GET("/resolve", handler.ResolveCitation)
 }
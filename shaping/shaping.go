@@ -0,0 +1,229 @@
+// Package shaping prepares Arabic text for renderers that draw glyphs one
+// after another from left to right without shaping support, such as PDF
+// writers. Letters are replaced by their contextual presentation forms and
+// lines are reordered into visual order.
+package shaping
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/hadith-api/normalize"
+)
+
+// forms holds the isolated, final, initial and medial presentation forms of a
+// letter. Letters that only join to the preceding letter have no initial or
+// medial form.
+type forms [4]rune
+
+const (
+	isolated = iota
+	final
+	initial
+	medial
+)
+
+// letters maps Arabic letters onto their presentation forms
+var letters = map[rune]forms{
+	'ء': {0xFE80, 0, 0, 0},
+	'آ': {0xFE81, 0xFE82, 0, 0},
+	'أ': {0xFE83, 0xFE84, 0, 0},
+	'ؤ': {0xFE85, 0xFE86, 0, 0},
+	'إ': {0xFE87, 0xFE88, 0, 0},
+	'ئ': {0xFE89, 0xFE8A, 0xFE8B, 0xFE8C},
+	'ا': {0xFE8D, 0xFE8E, 0, 0},
+	'ب': {0xFE8F, 0xFE90, 0xFE91, 0xFE92},
+	'ة': {0xFE93, 0xFE94, 0, 0},
+	'ت': {0xFE95, 0xFE96, 0xFE97, 0xFE98},
+	'ث': {0xFE99, 0xFE9A, 0xFE9B, 0xFE9C},
+	'ج': {0xFE9D, 0xFE9E, 0xFE9F, 0xFEA0},
+	'ح': {0xFEA1, 0xFEA2, 0xFEA3, 0xFEA4},
+	'خ': {0xFEA5, 0xFEA6, 0xFEA7, 0xFEA8},
+	'د': {0xFEA9, 0xFEAA, 0, 0},
+	'ذ': {0xFEAB, 0xFEAC, 0, 0},
+	'ر': {0xFEAD, 0xFEAE, 0, 0},
+	'ز': {0xFEAF, 0xFEB0, 0, 0},
+	'س': {0xFEB1, 0xFEB2, 0xFEB3, 0xFEB4},
+	'ش': {0xFEB5, 0xFEB6, 0xFEB7, 0xFEB8},
+	'ص': {0xFEB9, 0xFEBA, 0xFEBB, 0xFEBC},
+	'ض': {0xFEBD, 0xFEBE, 0xFEBF, 0xFEC0},
+	'ط': {0xFEC1, 0xFEC2, 0xFEC3, 0xFEC4},
+	'ظ': {0xFEC5, 0xFEC6, 0xFEC7, 0xFEC8},
+	'ع': {0xFEC9, 0xFECA, 0xFECB, 0xFECC},
+	'غ': {0xFECD, 0xFECE, 0xFECF, 0xFED0},
+	'ف': {0xFED1, 0xFED2, 0xFED3, 0xFED4},
+	'ق': {0xFED5, 0xFED6, 0xFED7, 0xFED8},
+	'ك': {0xFED9, 0xFEDA, 0xFEDB, 0xFEDC},
+	'ل': {0xFEDD, 0xFEDE, 0xFEDF, 0xFEE0},
+	'م': {0xFEE1, 0xFEE2, 0xFEE3, 0xFEE4},
+	'ن': {0xFEE5, 0xFEE6, 0xFEE7, 0xFEE8},
+	'ه': {0xFEE9, 0xFEEA, 0xFEEB, 0xFEEC},
+	'و': {0xFEED, 0xFEEE, 0, 0},
+	'ى': {0xFEEF, 0xFEF0, 0, 0},
+	'ي': {0xFEF1, 0xFEF2, 0xFEF3, 0xFEF4},
+	'ٱ': {0xFB50, 0xFB51, 0, 0},
+	'ـ': {'ـ', 'ـ', 'ـ', 'ـ'},
+}
+
+// lamAlef maps the alef variants onto the isolated and final forms of their ligature with lam
+var lamAlef = map[rune][2]rune{
+	'آ': {0xFEF5, 0xFEF6},
+	'أ': {0xFEF7, 0xFEF8},
+	'إ': {0xFEF9, 0xFEFA},
+	'ا': {0xFEFB, 0xFEFC},
+}
+
+// mirrored maps brackets onto their counterparts, which are shown in right-to-left text
+var mirrored = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'«': '»', '»': '«',
+	'<': '>', '>': '<',
+}
+
+// joinsNext reports whether a letter connects to the letter after it
+func joinsNext(r rune) bool {
+	f, ok := letters[r]
+	return ok && f[initial] != 0
+}
+
+// IsArabic reports whether s contains any Arabic letter
+func IsArabic(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Arabic, r) && !normalize.IsDiacritic(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// cluster is a letter with the diacritics written on it
+type cluster struct {
+	base  rune
+	marks []rune
+}
+
+// clusters splits a word into letters with their diacritics
+func clusters(word string) []cluster {
+	var result []cluster
+	for _, r := range word {
+		if normalize.IsDiacritic(r) && len(result) > 0 {
+			result[len(result)-1].marks = append(result[len(result)-1].marks, r)
+			continue
+		}
+		result = append(result, cluster{base: r})
+	}
+	return result
+}
+
+// Shape replaces the Arabic letters of a word by the presentation forms
+// matching their position, including the lam-alef ligatures. The result is
+// still in logical order.
+func Shape(word string) string {
+	cs := clusters(word)
+	shaped := make([]cluster, 0, len(cs))
+
+	for i := 0; i < len(cs); i++ {
+		c := cs[i]
+		f, ok := letters[c.base]
+		if !ok {
+			shaped = append(shaped, c)
+			continue
+		}
+
+		prevJoins := i > 0 && joinsNext(cs[i-1].base)
+
+		// Lam followed by alef becomes a single ligature
+		if c.base == 'ل' && i+1 < len(cs) {
+			if ligature, ok := lamAlef[cs[i+1].base]; ok {
+				form := ligature[0]
+				if prevJoins {
+					form = ligature[1]
+				}
+				marks := append(append([]rune{}, c.marks...), cs[i+1].marks...)
+				shaped = append(shaped, cluster{base: form, marks: marks})
+				i++
+				continue
+			}
+		}
+
+		nextJoins := i+1 < len(cs) && f[initial] != 0 && isLetter(cs[i+1].base)
+		var form rune
+		switch {
+		case prevJoins && nextJoins:
+			form = f[medial]
+		case prevJoins:
+			form = f[final]
+		case nextJoins:
+			form = f[initial]
+		default:
+			form = f[isolated]
+		}
+		shaped = append(shaped, cluster{base: form, marks: c.marks})
+	}
+
+	var b strings.Builder
+	for _, c := range shaped {
+		b.WriteRune(c.base)
+		for _, m := range c.marks {
+			b.WriteRune(m)
+		}
+	}
+	return b.String()
+}
+
+// isLetter reports whether r is an Arabic letter with presentation forms
+func isLetter(r rune) bool {
+	_, ok := letters[r]
+	return ok
+}
+
+// Visual shapes a line of right-to-left text and returns it in visual order,
+// to be drawn from left to right. Words are reversed, as are the letters of
+// Arabic words, while runs of digits and Latin words keep their order.
+// Diacritics are placed before their letter, as fonts draw zero-width marks
+// to the right of the pen position.
+func Visual(line string) string {
+	words := strings.Fields(line)
+	for i, word := range words {
+		words[i] = visualWord(word)
+	}
+	for i, j := 0, len(words)-1; i < j; i, j = i+1, j-1 {
+		words[i], words[j] = words[j], words[i]
+	}
+	return strings.Join(words, " ")
+}
+
+// visualWord reverses a shaped word, keeping digit and Latin runs in reading order
+func visualWord(word string) string {
+	cs := clusters(Shape(word))
+
+	// Split into runs that are reversed as a whole and runs kept left to right
+	var runs [][]cluster
+	var ltr []bool
+	for _, c := range cs {
+		isLTR := unicode.IsDigit(c.base) || (c.base < 0x0590 && unicode.IsLetter(c.base))
+		if n := len(runs); n > 0 && ltr[n-1] == isLTR && isLTR {
+			runs[n-1] = append(runs[n-1], c)
+			continue
+		}
+		runs = append(runs, []cluster{c})
+		ltr = append(ltr, isLTR)
+	}
+
+	var b strings.Builder
+	for i := len(runs) - 1; i >= 0; i-- {
+		for _, c := range runs[i] {
+			for _, m := range c.marks {
+				b.WriteRune(m)
+			}
+			if m, ok := mirrored[c.base]; ok {
+				b.WriteRune(m)
+			} else {
+				b.WriteRune(c.base)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package shaping
+
+import "testing"
+
+func TestShape(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		// Initial, medial and final forms
+		{"بسم", "ﺑﺴﻢ"},
+		// Alef does not join the letter after it
+		{"الله", "ﺍﻟﻠﻪ"},
+		// Lam-alef ligatures, isolated and joined to the letter before
+		{"لا", "ﻻ"},
+		{"سلام", "ﺳﻼﻡ"},
+		// Diacritics stay after their letter
+		{"بِ", "ﺏِ"},
+		{"abc", "abc"},
+	}
+
+	for _, tt := range tests {
+		if got := Shape(tt.in); got != tt.want {
+			t.Errorf("Shape(%q) = %+q, want %+q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestVisual(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"بسم", "ﻢﺴﺑ"},
+		// Words are reversed as well as letters
+		{"بسم الله", "ﻪﻠﻟﺍ ﻢﺴﺑ"},
+		// Diacritics are placed before their letter
+		{"بِ", "ِﺏ"},
+		// Digits keep their order, brackets are mirrored
+		{"رقم 12", "12 ﻢﻗﺭ"},
+		{"(١٢٣)", "(١٢٣)"},
+		{"Malik", "Malik"},
+		{"  ", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Visual(tt.in); got != tt.want {
+			t.Errorf("Visual(%q) = %+q, want %+q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsArabic(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"قال", true},
+		{"no. 12 قال", true},
+		{"Malik", false},
+		{"َِ", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsArabic(tt.in); got != tt.want {
+			t.Errorf("IsArabic(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,456 @@
+// Package static pre-renders the API's JSON responses into a directory tree
+// for static hosting, with precompressed gzip and brotli variants of every
+// file and a manifest listing them.
+package static
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/models"
+)
+
+// ManifestName is the name of the manifest at the root of the tree
+const ManifestName = "manifest.json"
+
+// NginxConfigName is the name of the nginx configuration written next to the manifest
+const NginxConfigName = "nginx.conf"
+
+// nginxConfig maps the URLs onto their files, which static hosts cannot do
+// on their own: a path is stored as the index.json of its directory and a
+// page as page=N.json. Any other query string, and every URL without a
+// file, goes to the API. The query is checked before it becomes part of the
+// file name, so that it cannot reach outside the tree.
+const nginxConfig = `# Written by build-static. Include it in the server block whose root is this
+# directory, and define the hadith_api upstream for the routes that are not
+# pre-rendered, e.g.
+#
+#     upstream hadith_api { server 127.0.0.1:8080; }
+location %[1]s/ {
+    error_page 418 = @hadith_api;
+    if ($args !~ "^(page=[0-9]+)?$") {
+        return 418;
+    }
+
+    set $static_file index;
+    if ($args) {
+        set $static_file $args;
+    }
+
+    gzip_static on;
+    # brotli_static on;  # with the ngx_brotli module
+    try_files $uri/$static_file.json @hadith_api;
+}
+
+location @hadith_api {
+    proxy_pass http://hadith_api;
+}
+`
+
+// skipped lists the routes that are not pre-rendered, relative to the base
+// path: responses that differ with every request or day, and downloads
+var skipped = map[string]bool{
+	"/hadis/random":     true,
+	"/hadis/daily":      true,
+	"/changes":          true,
+	"/bundle":           true,
+	"/export":           true,
+	"/export/:slug":     true,
+	"/export/:slug/tei": true,
+}
+
+// Source provides the values the route parameters are expanded with
+type Source interface {
+	GetAvailableNarrators() ([]string, error)
+	GetHadithsByNarrator(narrator string, params models.QueryParams) ([]models.Hadith, int, error)
+	GetTopics() ([]models.Topic, error)
+	GetClusters() ([]models.Cluster, error)
+	GetRevision() (*models.Revision, error)
+}
+
+// Manifest lists the rendered files and the routes left to the server
+type Manifest struct {
+	Revision  int       `json:"revision"`
+	CreatedAt time.Time `json:"created_at"`
+	// Dynamic lists the routes without rendered files, which still have to be served by the API
+	Dynamic []string `json:"dynamic"`
+	Files   []File   `json:"files"`
+}
+
+// File is a rendered response. The gzip and brotli variants are stored next
+// to it with a .gz and .br suffix.
+type File struct {
+	URL    string `json:"url"`
+	Path   string `json:"path"`
+	Hash   string `json:"hash"`
+	Size   int    `json:"size"`
+	Gzip   int    `json:"gzip"`
+	Brotli int    `json:"br"`
+}
+
+// builder renders the responses of a router into a directory
+type builder struct {
+	dir    string
+	router http.Handler
+
+	mu       sync.Mutex
+	files    []File
+	rendered map[string]bool
+	err      error
+}
+
+// job is a URL to render for a route
+type job struct {
+	route string
+	url   string
+}
+
+// Build renders every GET route of the router under base into dir, expanding
+// route parameters with the narrators, hadith numbers, topics and clusters of
+// the source and following the pages of paginated lists. The manifest and an
+// nginx configuration that serves the files at their URLs are written next to
+// them. A previous build in dir is replaced; any other non-empty directory is
+// refused.
+func Build(dir string, router *gin.Engine, base string, src Source) (*Manifest, error) {
+	if err := prepare(dir); err != nil {
+		return nil, err
+	}
+
+	revision, err := src.GetRevision()
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []string
+	for _, route := range router.Routes() {
+		if route.Method != http.MethodGet || !strings.HasPrefix(route.Path, base+"/") {
+			continue
+		}
+		routes = append(routes, route.Path)
+	}
+	sort.Strings(routes)
+
+	manifest := &Manifest{Revision: revision.Revision, CreatedAt: time.Now().UTC(), Dynamic: []string{}}
+	var jobs []job
+	for _, route := range routes {
+		if skipped[strings.TrimPrefix(route, base)] {
+			manifest.Dynamic = append(manifest.Dynamic, route)
+			continue
+		}
+		urls, err := expand(route, src)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range urls {
+			jobs = append(jobs, job{route: route, url: u})
+		}
+	}
+
+	b := &builder{dir: dir, router: router, rendered: make(map[string]bool)}
+	queue := make(chan job)
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				if err := b.renderPages(j); err != nil {
+					b.fail(err)
+				}
+			}
+		}()
+	}
+	for _, j := range jobs {
+		queue <- j
+	}
+	close(queue)
+	wg.Wait()
+
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	// Routes that rendered nothing, e.g. those requiring query parameters, stay dynamic
+	for _, route := range routes {
+		if !skipped[strings.TrimPrefix(route, base)] && !b.rendered[route] {
+			manifest.Dynamic = append(manifest.Dynamic, route)
+		}
+	}
+	sort.Strings(manifest.Dynamic)
+
+	sort.Slice(b.files, func(i, j int) bool { return b.files[i].URL < b.files[j].URL })
+	manifest.Files = b.files
+
+	if err := writeManifest(filepath.Join(dir, ManifestName), manifest); err != nil {
+		return nil, err
+	}
+	config := fmt.Sprintf(nginxConfig, base)
+	if err := os.WriteFile(filepath.Join(dir, NginxConfigName), []byte(config), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", NginxConfigName, err)
+	}
+
+	return manifest, nil
+}
+
+// prepare empties dir for a new build. Only a directory holding a manifest is
+// removed, so that a mistyped path cannot wipe unrelated files.
+func prepare(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return os.MkdirAll(dir, 0755)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ManifestName)); err != nil {
+		return fmt.Errorf("%s is not empty and holds no previous build", dir)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove previous build in %s: %w", dir, err)
+	}
+	return os.MkdirAll(dir, 0755)
+}
+
+// expand substitutes the parameters of a route with every known value. The
+// hadith numbers are those of the narrator the route names.
+func expand(route string, src Source) ([]string, error) {
+	urls := []string{""}
+	var narrators []string
+
+	for _, segment := range strings.Split(strings.TrimPrefix(route, "/"), "/") {
+		if !strings.HasPrefix(segment, ":") {
+			for i := range urls {
+				urls[i] += "/" + segment
+			}
+			continue
+		}
+
+		var next []string
+		switch segment {
+		case ":slug":
+			if narrators == nil {
+				list, err := src.GetAvailableNarrators()
+				if err != nil {
+					return nil, err
+				}
+				sort.Strings(list)
+				narrators = list
+			}
+			for _, u := range urls {
+				for _, narrator := range narrators {
+					next = append(next, u+"/"+narrator)
+				}
+			}
+		case ":number":
+			for _, u := range urls {
+				narrator := u[strings.LastIndex(u, "/")+1:]
+				hadiths, _, err := src.GetHadithsByNarrator(narrator, models.QueryParams{})
+				if err != nil {
+					return nil, err
+				}
+				for _, h := range hadiths {
+					next = append(next, u+"/"+url.PathEscape(h.Number.String()))
+				}
+			}
+		case ":topic":
+			topics, err := src.GetTopics()
+			if err != nil {
+				return nil, err
+			}
+			for _, u := range urls {
+				for _, topic := range topics {
+					next = append(next, u+"/"+topic.Slug)
+				}
+			}
+		case ":id":
+			clusters, err := src.GetClusters()
+			if err != nil {
+				return nil, err
+			}
+			for _, u := range urls {
+				for _, cluster := range clusters {
+					next = append(next, u+"/"+strconv.Itoa(cluster.ID))
+				}
+			}
+		default:
+			return nil, fmt.Errorf("no values to expand %s in route %s", segment, route)
+		}
+		urls = next
+	}
+
+	return urls, nil
+}
+
+// renderPages renders a URL and, when its response is paginated, the URLs of
+// the following pages
+func (b *builder) renderPages(j job) error {
+	body, ok, err := b.render(j, j.url)
+	if err != nil || !ok {
+		return err
+	}
+
+	var list struct {
+		Pagination *models.Pagination `json:"pagination"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil || list.Pagination == nil {
+		return nil
+	}
+	for page := 2; page <= list.Pagination.TotalPages; page++ {
+		if _, _, err := b.render(j, j.url+"?page="+strconv.Itoa(page)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// render requests a URL from the router and writes a successful JSON
+// response with its compressed variants. It reports whether a file was written.
+func (b *builder) render(j job, target string) ([]byte, bool, error) {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Accept", gin.MIMEJSON)
+	rec := httptest.NewRecorder()
+	b.router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), gin.MIMEJSON) {
+		return nil, false, nil
+	}
+	body := rec.Body.Bytes()
+
+	path := filePath(target)
+	full := filepath.Join(b.dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return nil, false, fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(full, body, 0644); err != nil {
+		return nil, false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	gz, err := compress(full+".gz", body, func(buf *bytes.Buffer) (writeCloser, error) {
+		return gzip.NewWriterLevel(buf, gzip.BestCompression)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	br, err := compress(full+".br", body, func(buf *bytes.Buffer) (writeCloser, error) {
+		return brotli.NewWriterLevel(buf, brotli.BestCompression), nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	sum := sha256.Sum256(body)
+	b.mu.Lock()
+	b.files = append(b.files, File{
+		URL:    target,
+		Path:   path,
+		Hash:   hex.EncodeToString(sum[:8]),
+		Size:   len(body),
+		Gzip:   gz,
+		Brotli: br,
+	})
+	b.rendered[j.route] = true
+	b.mu.Unlock()
+
+	return body, true, nil
+}
+
+// fail records the first error of the workers
+func (b *builder) fail(err error) {
+	b.mu.Lock()
+	if b.err == nil {
+		b.err = err
+	}
+	b.mu.Unlock()
+}
+
+// writeCloser is a compressing writer
+type writeCloser interface {
+	Write(p []byte) (int, error)
+	Close() error
+}
+
+// compress writes data compressed by the writer newWriter creates to path
+// and returns the compressed size
+func compress(path string, data []byte, newWriter func(buf *bytes.Buffer) (writeCloser, error)) (int, error) {
+	var buf bytes.Buffer
+	w, err := newWriter(&buf)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return 0, fmt.Errorf("failed to compress %s: %w", path, err)
+	}
+	if err := w.Close(); err != nil {
+		return 0, fmt.Errorf("failed to compress %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return buf.Len(), nil
+}
+
+// filePath maps a URL onto its file in the tree. A path is stored as the
+// index.json of its directory, since paths such as /hadis/bukhari are also
+// the parents of others; a query string becomes the file name, so that
+// /hadis/bukhari?page=2 is stored as hadis/bukhari/page=2.json.
+func filePath(target string) string {
+	path, query, _ := strings.Cut(strings.TrimPrefix(target, "/"), "?")
+	if query == "" {
+		return path + "/index.json"
+	}
+	return path + "/" + query + ".json"
+}
+
+// writeManifest writes the manifest with one file per line
+func writeManifest(path string, m *Manifest) error {
+	header, err := json.Marshal(struct {
+		Revision  int       `json:"revision"`
+		CreatedAt time.Time `json:"created_at"`
+		Dynamic   []string  `json:"dynamic"`
+	}{m.Revision, m.CreatedAt, m.Dynamic})
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	// Reuse the header fields and append the files array
+	out := header[:len(header)-1]
+	out = append(out, []byte(",\"files\":[")...)
+	for i, file := range m.Files {
+		encoded, err := json.Marshal(file)
+		if err != nil {
+			return fmt.Errorf("failed to encode manifest entry %s: %w", file.URL, err)
+		}
+		if i > 0 {
+			out = append(out, ',')
+		}
+		out = append(out, '\n')
+		out = append(out, encoded...)
+	}
+	out = append(out, []byte("\n]}\n")...)
+
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest %s: %w", path, err)
+	}
+	return nil
+}
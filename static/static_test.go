@@ -0,0 +1,149 @@
+package static
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/hadith-api/handlers"
+	"github.com/hadith-api/repository"
+	"github.com/hadith-api/routes"
+)
+
+func TestFilePath(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"/x", "x/index.json"},
+		{"/api/v1/narrators", "api/v1/narrators/index.json"},
+		{"/api/v1/hadis/alpha/2a", "api/v1/hadis/alpha/2a/index.json"},
+		{"/api/v1/hadis/alpha?page=2", "api/v1/hadis/alpha/page=2.json"},
+	}
+
+	for _, tt := range tests {
+		if got := filePath(tt.url); got != tt.want {
+			t.Errorf("filePath(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	repo := repository.NewFileRepository("../repository/testdata/data")
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes.SetupHadithRoutes(router.Group("/api/v1"), handlers.NewHadithHandler(repo))
+
+	dir := filepath.Join(t.TempDir(), "static-api")
+	manifest, err := Build(dir, router, "/api/v1", repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dynamic := make(map[string]bool)
+	for _, route := range manifest.Dynamic {
+		dynamic[route] = true
+	}
+	// Skipped routes, and routes that need query parameters
+	for _, route := range []string{"/api/v1/hadis/random", "/api/v1/hadis/daily", "/api/v1/export/:slug", "/api/v1/resolve"} {
+		if !dynamic[route] {
+			t.Errorf("%s is not dynamic: %v", route, manifest.Dynamic)
+		}
+	}
+	for _, route := range []string{"/api/v1/narrators", "/api/v1/hadis/:slug/:number"} {
+		if dynamic[route] {
+			t.Errorf("%s is dynamic", route)
+		}
+	}
+
+	files := make(map[string]File)
+	for _, f := range manifest.Files {
+		files[f.URL] = f
+	}
+	for _, url := range []string{"/api/v1/narrators", "/api/v1/hadis/alpha", "/api/v1/hadis/alpha/2a", "/api/v1/hadis/beta/3"} {
+		f, ok := files[url]
+		if !ok {
+			t.Errorf("%s was not rendered", url)
+			continue
+		}
+		if f.Path != filePath(url) {
+			t.Errorf("%s is stored as %s, want %s", url, f.Path, filePath(url))
+		}
+		checkFile(t, filepath.Join(dir, filepath.FromSlash(f.Path)), f)
+	}
+
+	var written Manifest
+	data, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatal(err)
+	}
+	if len(written.Files) != len(manifest.Files) || len(written.Dynamic) != len(manifest.Dynamic) {
+		t.Errorf("manifest.json lists %d files and %d dynamic routes, want %d and %d",
+			len(written.Files), len(written.Dynamic), len(manifest.Files), len(manifest.Dynamic))
+	}
+	config, err := os.ReadFile(filepath.Join(dir, NginxConfigName))
+	if err != nil || !strings.Contains(string(config), "location /api/v1/ {") {
+		t.Errorf("nginx.conf = %q, %v; want a location for /api/v1/", config, err)
+	}
+
+	// A build replaces the previous one, but no other directory
+	if _, err := Build(dir, router, "/api/v1", repo); err != nil {
+		t.Errorf("rebuild: %v", err)
+	}
+	other := t.TempDir()
+	if err := os.WriteFile(filepath.Join(other, "notes.txt"), []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Build(other, router, "/api/v1", repo); err == nil {
+		t.Error("Build into a non-empty directory without a manifest: want an error")
+	}
+}
+
+// checkFile checks a rendered file and that its .gz and .br variants hold the same content
+func checkFile(t *testing.T, path string, f File) {
+	t.Helper()
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(body) != f.Size || !json.Valid(body) {
+		t.Errorf("%s holds %d bytes of JSON, want %d", f.Path, len(body), f.Size)
+	}
+
+	variants := []struct {
+		suffix string
+		size   int
+		open   func(r io.Reader) (io.Reader, error)
+	}{
+		{".gz", f.Gzip, func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{".br", f.Brotli, func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil }},
+	}
+	for _, v := range variants {
+		compressed, err := os.ReadFile(path + v.suffix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(compressed) != v.size {
+			t.Errorf("%s%s holds %d bytes, want %d", f.Path, v.suffix, len(compressed), v.size)
+		}
+		r, err := v.open(bytes.NewReader(compressed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		decompressed, err := io.ReadAll(r)
+		if err != nil || !bytes.Equal(decompressed, body) {
+			t.Errorf("%s%s does not decompress to the file: %v", f.Path, v.suffix, err)
+		}
+	}
+}
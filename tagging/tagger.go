@@ -0,0 +1,123 @@
+// Package tagging assigns topics to hadiths with weighted keyword rules over
+// the normalized Arabic text and the Indonesian translation
+package tagging
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/normalize"
+)
+
+// Rules is the editable tagging configuration, usually meta/tagging_rules.json
+type Rules struct {
+	// Threshold is the minimum confidence for a tag to be assigned
+	Threshold float64               `json:"threshold"`
+	Topics    map[string]TopicRules `json:"topics"`
+}
+
+// TopicRules lists the keywords that indicate a topic
+type TopicRules struct {
+	Keywords []Keyword `json:"keywords"`
+}
+
+// Keyword is a word or phrase, in Arabic or Indonesian, with the weight it adds
+// to its topic's score. A trailing "*" matches any word starting with the term.
+type Keyword struct {
+	Term   string  `json:"term"`
+	Weight float64 `json:"weight"`
+}
+
+// LoadRules reads tagging rules from a JSON file
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tagging rules %s: %w", path, err)
+	}
+
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse tagging rules %s: %w", path, err)
+	}
+
+	return &rules, nil
+}
+
+// compiledKeyword is a keyword split into normalized words
+type compiledKeyword struct {
+	words  []string
+	prefix bool
+	weight float64
+}
+
+// Tagger assigns topics to hadiths according to a set of rules
+type Tagger struct {
+	threshold float64
+	topics    []string
+	keywords  map[string][]compiledKeyword
+}
+
+// NewTagger compiles the rules into a tagger
+func NewTagger(rules *Rules) *Tagger {
+	t := &Tagger{
+		threshold: rules.Threshold,
+		keywords:  make(map[string][]compiledKeyword),
+	}
+
+	for topic, topicRules := range rules.Topics {
+		t.topics = append(t.topics, topic)
+		for _, keyword := range topicRules.Keywords {
+			words, prefix := normalize.Term(keyword.Term)
+			if len(words) == 0 {
+				continue
+			}
+
+			t.keywords[topic] = append(t.keywords[topic], compiledKeyword{
+				words:  words,
+				prefix: prefix,
+				weight: keyword.Weight,
+			})
+		}
+	}
+	sort.Strings(t.topics)
+
+	return t
+}
+
+// Tag returns the topics whose confidence reaches the threshold, most confident first.
+// Confidence grows with the summed weight of the distinct keywords found: 1 - e^-score.
+func (t *Tagger) Tag(h models.Hadith) []models.Tag {
+	tokens := normalize.TokenForms(normalize.Tokenize(h.Arab + " " + h.ID))
+
+	var tags []models.Tag
+	for _, topic := range t.topics {
+		var score float64
+		for _, keyword := range t.keywords[topic] {
+			if keyword.matches(tokens) {
+				score += keyword.weight
+			}
+		}
+
+		confidence := math.Round((1-math.Exp(-score))*100) / 100
+		if score > 0 && confidence >= t.threshold {
+			tags = append(tags, models.Tag{Topic: topic, Confidence: confidence})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].Confidence > tags[j].Confidence })
+	return tags
+}
+
+// matches reports whether the keyword occurs as a word sequence in the tokens
+func (k compiledKeyword) matches(tokens [][]string) bool {
+	for i := range tokens {
+		if normalize.MatchForms(tokens, i, k.words, k.prefix) {
+			return true
+		}
+	}
+	return false
+}
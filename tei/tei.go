@@ -0,0 +1,237 @@
+// Package tei converts hadith collections to and from TEI XML, the Text
+// Encoding Initiative format used for scholarly interchange. A collection is
+// a <div type="collection"> holding optional book and chapter divs, and each
+// hadith is a <div type="hadith"> with its number in n, the Arabic text in a
+// <p xml:lang="ar"> and the translation in a <p xml:lang="id">.
+package tei
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/hadith-api/models"
+)
+
+// Namespace is the TEI XML namespace
+const Namespace = "http://www.tei-c.org/ns/1.0"
+
+// Header is the bibliographic description of an exported collection
+type Header struct {
+	Slug   string
+	Title  string
+	Author string
+	// Source describes where the text comes from
+	Source string
+}
+
+// Writer streams a collection as a TEI document
+type Writer struct {
+	enc     *xml.Encoder
+	book    string
+	chapter string
+	// open counts the book and chapter divs currently open
+	open int
+	err  error
+}
+
+// NewWriter writes the TEI header and opens the body of the document
+func NewWriter(w io.Writer, header Header) (*Writer, error) {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return nil, err
+	}
+
+	t := &Writer{enc: xml.NewEncoder(w)}
+	t.enc.Indent("", "  ")
+
+	title := header.Title
+	if title == "" {
+		title = header.Slug
+	}
+	source := header.Source
+	if source == "" {
+		source = "Hadith API"
+	}
+
+	t.start("TEI", xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: Namespace})
+	t.start("teiHeader")
+	t.start("fileDesc")
+	t.start("titleStmt")
+	t.element("title", title)
+	if header.Author != "" {
+		t.element("author", header.Author)
+	}
+	t.end("titleStmt")
+	t.start("publicationStmt")
+	t.element("p", "Hadith API")
+	t.end("publicationStmt")
+	t.start("sourceDesc")
+	t.element("p", source)
+	t.end("sourceDesc")
+	t.end("fileDesc")
+	t.end("teiHeader")
+	t.start("text")
+	t.start("body")
+	t.start("div", attr("type", "collection"), attr("n", header.Slug))
+
+	return t, t.flush()
+}
+
+// WriteHadith writes a hadith, opening book and chapter divs as its headings change
+func (t *Writer) WriteHadith(h models.Hadith) error {
+	if h.Book != t.book {
+		t.closeDivs(0)
+		t.book, t.chapter = h.Book, ""
+		if h.Book != "" {
+			t.start("div", attr("type", "book"))
+			t.element("head", h.Book)
+			t.open++
+		}
+	}
+	if h.Chapter != t.chapter {
+		if t.book != "" {
+			t.closeDivs(1)
+		} else {
+			t.closeDivs(0)
+		}
+		t.chapter = h.Chapter
+		if h.Chapter != "" {
+			t.start("div", attr("type", "chapter"))
+			t.element("head", h.Chapter)
+			t.open++
+		}
+	}
+
+	t.start("div", attr("type", "hadith"), attr("n", h.Number.String()))
+	t.element("p", h.Arab, xml.Attr{Name: xml.Name{Local: "xml:lang"}, Value: "ar"})
+	t.element("p", h.ID, xml.Attr{Name: xml.Name{Local: "xml:lang"}, Value: "id"})
+	t.end("div")
+
+	return t.flush()
+}
+
+// Close closes all open elements and flushes the document
+func (t *Writer) Close() error {
+	t.closeDivs(0)
+	t.end("div")
+	t.end("body")
+	t.end("text")
+	t.end("TEI")
+	return t.flush()
+}
+
+// closeDivs closes open book and chapter divs until keep remain
+func (t *Writer) closeDivs(keep int) {
+	for t.open > keep {
+		t.end("div")
+		t.open--
+	}
+}
+
+// flush writes out the encoded tokens and returns the first error encountered
+func (t *Writer) flush() error {
+	if t.err == nil {
+		t.err = t.enc.Flush()
+	}
+	return t.err
+}
+
+func (t *Writer) encode(token xml.Token) {
+	if t.err == nil {
+		t.err = t.enc.EncodeToken(token)
+	}
+}
+
+func (t *Writer) start(name string, attrs ...xml.Attr) {
+	t.encode(xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs})
+}
+
+func (t *Writer) end(name string) {
+	t.encode(xml.EndElement{Name: xml.Name{Local: name}})
+}
+
+func (t *Writer) element(name, text string, attrs ...xml.Attr) {
+	t.start(name, attrs...)
+	t.encode(xml.CharData(text))
+	t.end(name)
+}
+
+func attr(name, value string) xml.Attr {
+	return xml.Attr{Name: xml.Name{Local: name}, Value: value}
+}
+
+// Import reads a TEI document and returns the hadiths it contains with their
+// book and chapter headings. Hadiths are the divs of type "hadith"; their
+// paragraphs in Arabic become the Arabic text and all others the translation.
+func Import(r io.Reader) ([]models.Hadith, error) {
+	var doc struct {
+		Divs []div `xml:"text>body>div"`
+	}
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse TEI document: %w", err)
+	}
+
+	hadiths := []models.Hadith{}
+	for _, d := range doc.Divs {
+		if err := d.collect(&hadiths, "", ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return hadiths, nil
+}
+
+// div is a TEI division at any level
+type div struct {
+	Type       string      `xml:"type,attr"`
+	N          string      `xml:"n,attr"`
+	Head       string      `xml:"head"`
+	Paragraphs []paragraph `xml:"p"`
+	Divs       []div       `xml:"div"`
+}
+
+// paragraph is a TEI paragraph with its language
+type paragraph struct {
+	Lang string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Text string `xml:",chardata"`
+}
+
+// collect appends the hadiths in a div and its descendants
+func (d div) collect(hadiths *[]models.Hadith, book, chapter string) error {
+	switch d.Type {
+	case "book":
+		book, chapter = d.Head, ""
+	case "chapter":
+		chapter = d.Head
+	case "hadith":
+		number, err := models.ParseNumber(d.N)
+		if err != nil {
+			return fmt.Errorf("hadith without a valid number: %w", err)
+		}
+
+		h := models.Hadith{Number: number, Book: book, Chapter: chapter}
+		for _, p := range d.Paragraphs {
+			if p.Lang == "ar" {
+				h.Arab = joinText(h.Arab, p.Text)
+			} else {
+				h.ID = joinText(h.ID, p.Text)
+			}
+		}
+		*hadiths = append(*hadiths, h)
+		return nil
+	}
+
+	for _, child := range d.Divs {
+		if err := child.collect(hadiths, book, chapter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func joinText(text, more string) string {
+	if text == "" {
+		return more
+	}
+	return text + "\n" + more
+}
@@ -0,0 +1,80 @@
+package tei
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hadith-api/models"
+)
+
+func TestRoundTrip(t *testing.T) {
+	hadiths := []models.Hadith{
+		{Number: models.Number{Value: 1}, Book: "Kitab ash-Shalah", Chapter: "Bab waktu shalat",
+			Arab: "قَالَ <النَّبِيُّ> & صَلَّى", ID: "Shalat pada waktunya, a < b & c"},
+		{Number: models.Number{Value: 2}, Book: "Kitab ash-Shalah", Chapter: "Bab niat",
+			Arab: "إِنَّمَا الْأَعْمَالُ بِالنِّيَّاتِ", ID: "Amal itu tergantung niatnya."},
+		{Number: models.Number{Value: 2, Suffix: "a"}, Book: "Kitab ash-Shalah", Chapter: "Bab niat",
+			Arab: "وَإِنَّمَا لِكُلِّ امْرِئٍ مَا نَوَى", ID: "Setiap orang mendapat apa yang ia niatkan."},
+		// A book without chapters, then hadiths without headings
+		{Number: models.Number{Value: 3}, Book: "Kitab ash-Shiyam",
+			Arab: "الصَّوْمُ جُنَّةٌ", ID: "Puasa itu perisai."},
+		{Number: models.Number{Value: 4},
+			Arab: "الدِّينُ النَّصِيحَةُ", ID: "Agama itu nasihat."},
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Header{Slug: "alpha", Title: "Kitab Alpha", Author: "Imam Alpha"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range hadiths {
+		if err := w.WriteHadith(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := buf.String()
+	for _, want := range []string{
+		`<div type="collection" n="alpha">`,
+		`<div type="hadith" n="2a">`,
+		"قَالَ &lt;النَّبِيُّ&gt; &amp; صَلَّى",
+		"a &lt; b &amp; c",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("document does not contain %s:\n%s", want, doc)
+		}
+	}
+	if got := strings.Count(doc, `<div type="book">`); got != 2 {
+		t.Errorf("document has %d book divs, want 2", got)
+	}
+	if got := strings.Count(doc, `<div type="chapter">`); got != 2 {
+		t.Errorf("document has %d chapter divs, want 2", got)
+	}
+
+	got, err := Import(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, hadiths) {
+		t.Errorf("Import = %+v, want %+v", got, hadiths)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	tests := []string{
+		"<TEI><text><body><div type=\"hadith\" n=\"x\"></div></body></text></TEI>",
+		"<TEI><text><body><div type=\"hadith\" n=\"\"></div></body></text></TEI>",
+		"<TEI><text><body>",
+	}
+
+	for _, doc := range tests {
+		if _, err := Import(strings.NewReader(doc)); err == nil {
+			t.Errorf("Import(%q): want an error", doc)
+		}
+	}
+}
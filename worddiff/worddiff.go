@@ -0,0 +1,114 @@
+// Package worddiff computes word-level differences between two texts
+package worddiff
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/hadith-api/models"
+	"github.com/hadith-api/normalize"
+)
+
+// Operations of a diff
+const (
+	OpEqual  = "equal"
+	OpDelete = "delete"
+	OpInsert = "insert"
+)
+
+// Key returns the form a word is compared in
+type Key func(word string) string
+
+// Normalized compares words after normalization, ignoring diacritics,
+// letter variants, case and punctuation
+func Normalized(word string) string {
+	return strings.Join(normalize.Tokenize(word), "")
+}
+
+// Preserved compares Arabic words with their diacritics, ignoring only
+// punctuation around the word
+func Preserved(word string) string {
+	return strings.TrimFunc(word, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSymbol(r)
+	})
+}
+
+// Diff compares the words of a and b and returns the operations turning a
+// into b, with runs of the same operation merged, and the share of words the
+// texts have in common
+func Diff(a, b string, key Key) models.TextDiff {
+	wordsA, wordsB := strings.Fields(a), strings.Fields(b)
+	keysA, keysB := keys(wordsA, key), keys(wordsB, key)
+
+	// lcs[i][j] is the length of the longest common subsequence of keysA[i:] and keysB[j:]
+	lcs := make([][]int32, len(keysA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(keysB)+1)
+	}
+	for i := len(keysA) - 1; i >= 0; i-- {
+		for j := len(keysB) - 1; j >= 0; j-- {
+			if keysA[i] == keysB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	result := models.TextDiff{Ops: []models.DiffOp{}}
+	add := func(op, wordA, wordB string) {
+		if n := len(result.Ops); n > 0 && result.Ops[n-1].Op == op {
+			last := &result.Ops[n-1]
+			last.A = join(last.A, wordA)
+			last.B = join(last.B, wordB)
+			return
+		}
+		result.Ops = append(result.Ops, models.DiffOp{Op: op, A: wordA, B: wordB})
+	}
+
+	i, j := 0, 0
+	for i < len(keysA) && j < len(keysB) {
+		switch {
+		case keysA[i] == keysB[j]:
+			add(OpEqual, wordsA[i], wordsB[j])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			add(OpDelete, wordsA[i], "")
+			i++
+		default:
+			add(OpInsert, "", wordsB[j])
+			j++
+		}
+	}
+	for ; i < len(keysA); i++ {
+		add(OpDelete, wordsA[i], "")
+	}
+	for ; j < len(keysB); j++ {
+		add(OpInsert, "", wordsB[j])
+	}
+
+	if total := len(keysA) + len(keysB); total > 0 {
+		result.Similarity = math.Round(2*float64(lcs[0][0])/float64(total)*1000) / 1000
+	}
+
+	return result
+}
+
+func keys(words []string, key Key) []string {
+	k := make([]string, len(words))
+	for i, word := range words {
+		k[i] = key(word)
+	}
+	return k
+}
+
+func join(text, word string) string {
+	if text == "" || word == "" {
+		return text + word
+	}
+	return text + " " + word
+}
@@ -0,0 +1,50 @@
+package worddiff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hadith-api/models"
+)
+
+func TestDiff(t *testing.T) {
+	equal := func(a, b string) models.DiffOp { return models.DiffOp{Op: OpEqual, A: a, B: b} }
+	del := func(a string) models.DiffOp { return models.DiffOp{Op: OpDelete, A: a} }
+	ins := func(b string) models.DiffOp { return models.DiffOp{Op: OpInsert, B: b} }
+
+	tests := []struct {
+		a, b string
+		key  Key
+		want models.TextDiff
+	}{
+		{"shalat pada waktunya", "shalat pada waktunya", Normalized,
+			models.TextDiff{Similarity: 1, Ops: []models.DiffOp{equal("shalat pada waktunya", "shalat pada waktunya")}}},
+		{"amal niat", "agama nasihat", Normalized,
+			models.TextDiff{Similarity: 0, Ops: []models.DiffOp{del("amal niat"), ins("agama nasihat")}}},
+		// Insertions and deletions at either end
+		{"pada waktunya", "shalat pada waktunya", Normalized,
+			models.TextDiff{Similarity: 0.8, Ops: []models.DiffOp{ins("shalat"), equal("pada waktunya", "pada waktunya")}}},
+		{"shalat pada", "shalat pada waktunya", Normalized,
+			models.TextDiff{Similarity: 0.8, Ops: []models.DiffOp{equal("shalat pada", "shalat pada"), ins("waktunya")}}},
+		{"shalat pada waktunya", "pada waktunya", Normalized,
+			models.TextDiff{Similarity: 0.8, Ops: []models.DiffOp{del("shalat"), equal("pada waktunya", "pada waktunya")}}},
+		{"shalat pada waktunya", "shalat pada", Normalized,
+			models.TextDiff{Similarity: 0.8, Ops: []models.DiffOp{equal("shalat pada", "shalat pada"), del("waktunya")}}},
+		// Arabic with diacritics is equal to the bare text once normalized,
+		// with each side kept as written
+		{"قَالَ رَسُولُ اللَّهِ", "قال رسول الله", Normalized,
+			models.TextDiff{Similarity: 1, Ops: []models.DiffOp{equal("قَالَ رَسُولُ اللَّهِ", "قال رسول الله")}}},
+		{"قَالَ رَسُولُ اللَّهِ", "قال رسول الله", Preserved,
+			models.TextDiff{Similarity: 0, Ops: []models.DiffOp{del("قَالَ رَسُولُ اللَّهِ"), ins("قال رسول الله")}}},
+		{"قَالَ: الدِّينُ", "قَالَ الدِّينُ", Preserved,
+			models.TextDiff{Similarity: 1, Ops: []models.DiffOp{equal("قَالَ: الدِّينُ", "قَالَ الدِّينُ")}}},
+		{"", "", Normalized, models.TextDiff{Ops: []models.DiffOp{}}},
+		{"", "الدين", Normalized, models.TextDiff{Ops: []models.DiffOp{ins("الدين")}}},
+	}
+
+	for _, tt := range tests {
+		if got := Diff(tt.a, tt.b, tt.key); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Diff(%q, %q) = %+v, want %+v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}